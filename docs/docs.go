@@ -679,7 +679,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a directory in Cloudflare R2 bucket",
+                "description": "Start a background job that moves a directory in Cloudflare R2 bucket to the trash (.trash/\u003ctime\u003e/\u003cdir\u003e/); list it with GET /api/v1/cloudflare/r2/trash and undo with POST /api/v1/cloudflare/r2/trash/restore. With hard (admin only) the directory is deleted permanently with batched DeleteObjects calls (1000 keys each). Track the job with GET /api/v1/jobs/{id}. With dryrun the object count and total size are returned and nothing is deleted",
                 "consumes": [
                     "application/json"
                 ],
@@ -718,13 +718,19 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Dry run result",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers or body",
+                        "description": "Invalid request headers or body, or directory already in the trash",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -735,6 +741,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Response"
                         }
                     },
+                    "403": {
+                        "description": "Hard delete requires admin",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Directory is empty or does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -751,7 +769,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Copy a directory in Cloudflare R2 bucket",
+                "description": "Start a background job that copies a directory in Cloudflare R2 bucket. Track it with GET /api/v1/jobs/{id}. With sync only objects missing at the target or differing in size or ETag are copied (counted in unchanged_objects otherwise); when either ETag comes from a multipart upload the contents are compared by MD5, and deleteextra also moves target objects that are not in the source to the trash of the target bucket (counted in deleted_objects; the trash entry is returned in trash_id and can be restored). deleteextra is refused when the target prefix is empty or the source prefix has no objects. With verify the job fails if the target does not match the source afterwards; mismatches are listed in errors",
                 "consumes": [
                     "application/json"
                 ],
@@ -789,14 +807,14 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers or body",
+                        "description": "Invalid request headers or body, overlapping directories, or deleteextra with an empty target directory or source",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -816,14 +834,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/cloudflare/kv/namespaces/keys": {
-            "post": {
+        "/api/v1/cloudflare/kv/namespaces": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of keys in a Cloudflare KV namespace",
+                "description": "Change the title of a KV namespace",
                 "consumes": [
                     "application/json"
                 ],
@@ -833,7 +851,7 @@ const docTemplate = `{
                 "tags": [
                     "cloudflare-kv"
                 ],
-                "summary": "Get KV namespace keys",
+                "summary": "Rename KV namespace",
                 "parameters": [
                     {
                         "type": "string",
@@ -851,12 +869,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Get KV keys request",
+                        "description": "Rename KV namespace request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.GetKVKeysRequest"
+                            "$ref": "#/definitions/handler.RenameKVNamespaceRequest"
                         }
                     }
                 ],
@@ -886,16 +904,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/cloudflare/kv/namespaces/keys/values": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update values for keys in a Cloudflare KV namespace",
+                "description": "Create a KV namespace in the Cloudflare account",
                 "consumes": [
                     "application/json"
                 ],
@@ -905,7 +921,7 @@ const docTemplate = `{
                 "tags": [
                     "cloudflare-kv"
                 ],
-                "summary": "Update KV namespace key values",
+                "summary": "Create KV namespace",
                 "parameters": [
                     {
                         "type": "string",
@@ -923,12 +939,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Update KV key values request",
+                        "description": "Create KV namespace request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateKVKeyValuesRequest"
+                            "$ref": "#/definitions/handler.CreateKVNamespaceRequest"
                         }
                     }
                 ],
@@ -959,13 +975,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get values for keys in a Cloudflare KV namespace",
+                "description": "Delete a KV namespace. Refused while a Pages project still binds it unless force is set",
                 "consumes": [
                     "application/json"
                 ],
@@ -975,7 +991,7 @@ const docTemplate = `{
                 "tags": [
                     "cloudflare-kv"
                 ],
-                "summary": "Get KV namespace key values",
+                "summary": "Delete KV namespace",
                 "parameters": [
                     {
                         "type": "string",
@@ -993,12 +1009,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Get KV key values request",
+                        "description": "Delete KV namespace request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.GetKVKeyValuesRequest"
+                            "$ref": "#/definitions/handler.DeleteKVNamespaceRequest"
                         }
                     }
                 ],
@@ -1021,6 +1037,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/model.Response"
                         }
                     },
+                    "409": {
+                        "description": "Namespace is still bound by Pages projects",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
                     "500": {
                         "description": "Server error",
                         "schema": {
@@ -1030,14 +1052,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/cloudflare/pages/info": {
-            "get": {
+        "/api/v1/cloudflare/kv/namespaces/bulk": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of Cloudflare Pages projects",
+                "description": "Write many key-value pairs to a Cloudflare KV namespace, split into chunks of 10,000 pairs. Previous values are read chunk by chunk and history is recorded for every key, or only for keys matching kv_history_key_patterns when it is configured",
                 "consumes": [
                     "application/json"
                 ],
@@ -1045,9 +1067,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cloudflare-pages"
+                    "cloudflare-kv"
                 ],
-                "summary": "Get Pages projects",
+                "summary": "Bulk update KV key values",
                 "parameters": [
                     {
                         "type": "string",
@@ -1063,6 +1085,15 @@ const docTemplate = `{
                         "type": "string",
                         "name": "env",
                         "in": "header"
+                    },
+                    {
+                        "description": "Bulk update KV key values request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkUpdateKVKeyValuesRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1073,7 +1104,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers",
+                        "description": "Invalid request headers or body, value failed schema validation, or too many keys to record history for",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1085,22 +1116,20 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Server error or some chunks failed",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/cloudflare/pages/projects": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of Cloudflare Pages projects",
+                "description": "Delete many keys from a Cloudflare KV namespace, split into chunks of 10,000 keys. Previous values are read chunk by chunk and history is recorded for every key, or only for keys matching kv_history_key_patterns when it is configured",
                 "consumes": [
                     "application/json"
                 ],
@@ -1108,9 +1137,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "cloudflare-pages"
+                    "cloudflare-kv"
                 ],
-                "summary": "Get Pages projects",
+                "summary": "Bulk delete KV keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -1126,6 +1155,15 @@ const docTemplate = `{
                         "type": "string",
                         "name": "env",
                         "in": "header"
+                    },
+                    {
+                        "description": "Bulk delete KV keys request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BulkDeleteKVKeysRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1136,7 +1174,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers",
+                        "description": "Invalid request headers or body, or too many keys to record history for",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1148,7 +1186,7 @@ const docTemplate = `{
                         }
                     },
                     "500": {
-                        "description": "Server error",
+                        "description": "Server error or some chunks failed",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1156,14 +1194,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/cloudflare/{accountId}/kv/namespaces": {
-            "get": {
+        "/api/v1/cloudflare/kv/namespaces/keys": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of KV namespaces for a Cloudflare account",
+                "description": "Get list of keys in a Cloudflare KV namespace, with prefix filter and cursor pagination (set all=true to walk every page)",
                 "consumes": [
                     "application/json"
                 ],
@@ -1173,7 +1211,7 @@ const docTemplate = `{
                 "tags": [
                     "cloudflare-kv"
                 ],
-                "summary": "Get KV namespaces",
+                "summary": "Get KV namespace keys",
                 "parameters": [
                     {
                         "type": "string",
@@ -1189,6 +1227,15 @@ const docTemplate = `{
                         "type": "string",
                         "name": "env",
                         "in": "header"
+                    },
+                    {
+                        "description": "Get KV keys request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetKVKeysRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1199,7 +1246,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers",
+                        "description": "Invalid request headers or body",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1219,14 +1266,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services": {
-            "get": {
+        "/api/v1/cloudflare/kv/namespaces/keys/history": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of all Function Compute services",
+                "description": "List recorded value changes of a Cloudflare KV key in the country and env of the request headers, newest first",
                 "consumes": [
                     "application/json"
                 ],
@@ -1234,17 +1281,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "cloudflare-kv"
                 ],
-                "summary": "List FC services",
+                "summary": "List KV key history",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1259,6 +1299,15 @@ const docTemplate = `{
                         "type": "string",
                         "name": "env",
                         "in": "header"
+                    },
+                    {
+                        "description": "List KV history request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListKVHistoryRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -1269,7 +1318,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers",
+                        "description": "Invalid request headers or body",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1289,14 +1338,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/aliases": {
-            "put": {
+        "/api/v1/cloudflare/kv/namespaces/keys/history/diff": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update service alias to point to a specific version",
+                "description": "Line diff between two revisions of a KV key, or between the old and new value of one revision. Revisions from another country or env are not found",
                 "consumes": [
                     "application/json"
                 ],
@@ -1304,17 +1353,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "aliases"
+                    "cloudflare-kv"
                 ],
-                "summary": "Update alias",
+                "summary": "Diff KV key history",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1331,12 +1373,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Update alias request",
+                        "description": "Diff KV history request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpdateAliasRequest"
+                            "$ref": "#/definitions/handler.DiffKVHistoryRequest"
                         }
                     }
                 ],
@@ -1366,14 +1408,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/v1/cloudflare/kv/namespaces/keys/history/restore": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of aliases for a specific service",
+                "description": "Restore a KV key to the value, metadata and expiration written by a revision (or those before it); only revisions of the request country and env can be restored, and the restore itself is recorded in history",
                 "consumes": [
                     "application/json"
                 ],
@@ -1381,17 +1425,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "aliases"
+                    "cloudflare-kv"
                 ],
-                "summary": "List aliases",
+                "summary": "Restore KV key history",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1408,12 +1445,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "List alias request",
+                        "description": "Restore KV history request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ListAliasRequest"
+                            "$ref": "#/definitions/handler.RestoreKVHistoryRequest"
                         }
                     }
                 ],
@@ -1445,14 +1482,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/functions": {
-            "post": {
+        "/api/v1/cloudflare/kv/namespaces/keys/values": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of functions for a specific service",
+                "description": "Update values for keys in a Cloudflare KV namespace, optionally with metadata and expiration. The previous value is recorded in the KV history; ProdVersion keys are tagged with updated_by/updated_at metadata.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1460,17 +1497,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "functions"
+                    "cloudflare-kv"
                 ],
-                "summary": "List functions",
+                "summary": "Update KV namespace key values",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1487,12 +1517,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "List fc request",
+                        "description": "Update KV key values request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.ListFcRequest"
+                            "$ref": "#/definitions/handler.UpdateKVKeyValuesRequest"
                         }
                     }
                 ],
@@ -1504,7 +1534,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers or body",
+                        "description": "Invalid request headers or body, or value failed schema validation",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1518,21 +1548,18 @@ const docTemplate = `{
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Response"
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/services/publish": {
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Publish a service version",
+                "description": "Get values for keys in a Cloudflare KV namespace; set withmetadata=true to get value, metadata and expiration as JSON",
                 "consumes": [
                     "application/json"
                 ],
@@ -1540,17 +1567,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "services"
+                    "cloudflare-kv"
                 ],
-                "summary": "Publish service",
+                "summary": "Get KV namespace key values",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1567,12 +1587,12 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "Publish service request",
+                        "description": "Get KV key values request",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.PublicServiceRequest"
+                            "$ref": "#/definitions/handler.GetKVKeyValuesRequest"
                         }
                     }
                 ],
@@ -1604,32 +1624,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/services/versions": {
-            "post": {
+        "/api/v1/cloudflare/kv/namespaces/{namespaceid}/export": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get list of versions for a specific service",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Stream every key of a KV namespace with its value, metadata and expiration. Non UTF-8 values are base64 encoded. X-KV-Key-Count is the number of listed keys; the file ends with a trailer holding the exported count (the last NDJSON line {\"trailer\":{...}}, or the trailer field in JSON). If reading values fails mid-stream the trailer carries the error, and import rejects files whose trailer is missing, failed or does not match",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson"
                 ],
                 "tags": [
-                    "services"
+                    "cloudflare-kv"
                 ],
-                "summary": "List service versions",
+                "summary": "Export KV namespace",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "name": "authorization",
@@ -1646,24 +1657,28 @@ const docTemplate = `{
                         "in": "header"
                     },
                     {
-                        "description": "List service version request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.ListServiceVersionRequest"
-                        }
+                        "type": "string",
+                        "description": "Namespace ID",
+                        "name": "namespaceid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ndjson (default) or json",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.Response"
+                            "type": "file"
                         }
                     },
                     "400": {
-                        "description": "Invalid request headers or body",
+                        "description": "Invalid request headers or format",
                         "schema": {
                             "$ref": "#/definitions/model.Response"
                         }
@@ -1677,221 +1692,4495 @@ const docTemplate = `{
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Response"
                         }
                     }
                 }
             }
         },
-        "/api/v1/system/zones": {
-            "get": {
+        "/api/v1/cloudflare/kv/namespaces/{namespaceid}/import": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Validate token and get user information along with available zones",
+                "description": "Restore a KV namespace from an NDJSON or JSON export, uploaded as multipart field \"file\" or as the raw request body. Files without a valid trailer (truncated or failed exports) are rejected",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data",
+                    "application/x-ndjson"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "cloudflare-kv"
                 ],
-                "summary": "Get zone information and validate token",
+                "summary": "Import KV namespace",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Bearer {token}",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Response with userid, username, environment, and country_code",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "name": "authorization",
+                        "in": "header"
                     },
-                    "401": {
-                        "description": "Unauthorized or invalid token",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace ID",
+                        "name": "namespaceid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "skip, overwrite or fail (default)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Export file",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers, mode or file, or incomplete export file",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Keys already exist (fail mode)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
                         }
                     },
                     "500": {
                         "description": "Server error",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/kv/promote": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Copy selected keys or a key prefix from a source namespace/environment to a target. Without confirm a diff preview is returned; with confirm and the preview hash the changes are applied, keeping the source metadata and expiration (keys whose source value is about to expire fail)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Promote KV keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Promote KV request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PromoteKVRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Source or target changed since preview",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/kv/schemas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List validation rules for KV values, optionally filtered by namespace",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "List KV value schemas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace ID",
+                        "name": "namespaceid",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Register a JSON Schema or regex rule for KV values matching a namespace and key pattern",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Create KV value schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "KV value schema",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.KVValueSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or rule",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/kv/schemas/validate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Check a value against the registered rules for a namespace and key without writing it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Validate KV value",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Validate KV value request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ValidateKVValueRequest"
                         }
                     }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or value failed validation",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/kv/schemas/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a KV value validation rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Update KV value schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "KV value schema",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.KVValueSchemaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or rule",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a KV value validation rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Delete KV value schema",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Schema ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid schema ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Schema not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/domains": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Custom domains and their status for every Pages project in the account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "List custom domains of all Pages projects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/info": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get Cloudflare Pages projects with every preview and production binding; KV bindings include the keys matching the configured key patterns",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Get Pages projects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated key patterns overriding the configured ones, e.g. *ProdVersion*,*Config",
+                        "name": "keypatterns",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of Cloudflare Pages projects",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Get Pages projects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "PATCH the build command and the preview/production deployment configs (env vars and secrets, KV, R2 and D1 bindings, compatibility date and flags). Without confirm the diff is returned; with confirm and the preview hash the changes are applied. Null map values remove an entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Update Pages project configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Pages project configuration request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdatePagesProjectConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Project configuration changed since preview",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/deployments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List deployments of a Pages project with branch, commit hash, status and aliases, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "List Pages deployments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "production or preview",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (max 25)",
+                        "name": "perpage",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get details and build stages of a Pages deployment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Get Pages deployment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deployment ID",
+                        "name": "deploymentid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the build logs of a Pages deployment. With stream=true or Accept: text/event-stream the logs are pushed as Server-Sent Events (log, status, done, error) until the deployment finishes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Get Pages deployment build logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deployment ID",
+                        "name": "deploymentid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Stream logs with Server-Sent Events",
+                        "name": "stream",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retry a failed Pages deployment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Retry Pages deployment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deployment ID",
+                        "name": "deploymentid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Roll the production environment of a Pages project back to an earlier successful production deployment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Roll back Pages production",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Deployment ID to roll back to",
+                        "name": "deploymentid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/domains": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List custom domains of a Pages project with verification and certificate validation status",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "List Pages project custom domains",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Add a custom domain to a Pages project. The returned validation data describes the DNS records still required",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Add Pages project custom domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Add custom domain request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AddPagesDomainRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/domains/{domainname}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the verification and certificate validation status of a custom domain",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Get Pages custom domain status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain name",
+                        "name": "domainname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a custom domain from a Pages project",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Remove Pages custom domain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain name",
+                        "name": "domainname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/pages/projects/{projectname}/domains/{domainname}/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Trigger a new verification of a custom domain that is pending or failed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-pages"
+                ],
+                "summary": "Retry Pages custom domain validation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Pages project name",
+                        "name": "projectname",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Domain name",
+                        "name": "domainname",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/copy": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a background job that copies every object under the source prefix to the target prefix, retrying transient failures. Track it with GET /api/v1/jobs/{id}; an interrupted job resumes after the last completed key. Source and target may be in different environments and Cloudflare accounts; the same account uses server-side copy, different accounts stream through this service. With sync only objects missing at the target or differing in size or ETag are copied (counted in unchanged_objects otherwise); when either ETag comes from a multipart upload the contents are compared by MD5, and deleteextra also moves target objects that are not in the source to the trash of the target bucket (counted in deleted_objects; the trash entry is returned in trash_id and can be restored). deleteextra is refused when the target prefix is empty or the source prefix has no objects. With verify the job fails if the target does not match the source afterwards; mismatches are listed in errors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Copy R2 objects across buckets and accounts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Copy R2 objects request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CopyR2ObjectsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, overlapping prefixes, or deleteextra with an empty target prefix or source",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/objects": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List folders and objects (size, ETag, last-modified, storage class) under a prefix with continuation-token paging. filter=versions keeps only version directories (latest or v*)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Browse R2 objects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucketname",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key prefix, e.g. v1.2.3/",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delimiter used to group folders, e.g. /",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token from the previous page",
+                        "name": "continuationtoken",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (1-1000)",
+                        "name": "pagesize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "versions",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/objects/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Extract a tar, tar.gz or zip archive from a multipart \"file\" field or the raw request body and upload every file under the prefix. The format is detected from the content unless given. The archive size, the total extracted size and the file count are limited by r2_archive_max_bytes (default 2GB) and r2_archive_max_entries (default 50000). If the upload fails part way, the files already uploaded are deleted; data reports uploaded and removed counts and lists any key that could not be removed in remaining",
+                "consumes": [
+                    "application/octet-stream",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Upload and extract archive to R2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucketname",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target prefix, e.g. v1.2.3/",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "tar, tgz or zip",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Archive (or send it as the raw body)",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "413": {
+                        "description": "Archive too large",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error, or upload failed and uploaded files were removed",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/objects/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream an object. A Range header (e.g. bytes=0-1023) returns 206 with Content-Range",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Download R2 object",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Byte range, e.g. bytes=0-1023",
+                        "name": "Range",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucketname",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Partial Content",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Object not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "416": {
+                        "description": "Range not satisfiable",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/objects/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Upload a single object from a multipart \"file\" field or the raw request body. Objects larger than 16MB use multipart upload. Content-Type is detected from the extension and content unless given",
+                "consumes": [
+                    "application/octet-stream",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Upload R2 object",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucketname",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Object key, e.g. v1.2.3/index.html",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Content-Type of the object",
+                        "name": "contenttype",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Object content (or send it as the raw body)",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/presign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Return a time-limited presigned GET or PUT URL for an object. The expiry defaults to r2_presign_default_expiry and may not exceed r2_presign_max_expiry. Returned headers must be sent with the request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Presign R2 object URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Presign request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PresignR2ObjectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body, or expiry not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/retention": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the version directory retention policies of all buckets in the environment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "List R2 retention policies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create or update the retention policy of a bucket: keep the newest N vX.Y.Z directories plus latest and every version referenced by a KV key matching kv_key_patterns. With intervalhours the policy is executed on that schedule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Save R2 retention policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Retention policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.R2RetentionPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the retention policy of a bucket. Delete jobs already created are not affected",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Delete R2 retention policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Bucket",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetBucketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Retention policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/retention/execute": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a background job moving every version directory the retention policy does not keep to the trash, where it can be restored until the trash is purged. Track them with GET /api/v1/jobs/{id}. keep overrides the configured count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Execute R2 retention",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Retention request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.R2RetentionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Retention policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/retention/preview": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the version directories the retention policy would keep (with the reason) and delete (with object count and size). Nothing is deleted. keep overrides the configured count",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Preview R2 retention",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Retention request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.R2RetentionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Retention policy not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/trash": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List directories moved to the trash of a bucket, newest first, with the time they were deleted and when they will be purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "List R2 trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Bucket name",
+                        "name": "bucketname",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or query",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/trash/purge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start background delete jobs for trash entries older than r2_trash_retention_hours (default 168). The same purge runs every hour for every bucket that has used the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Purge R2 trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Bucket",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GetBucketRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/trash/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a background job that moves a trash entry back to its original directory. Refused if the directory has objects again or the entry is still being processed by another job. Track it with GET /api/v1/jobs/{id}",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Restore R2 directory from trash",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Restore request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RestoreR2TrashRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Trash entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Directory not empty or entry busy",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/r2/verify": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Compare the objects under the source and target prefixes: object counts, sizes and ETags. Multipart objects whose ETags differ are compared by the MD5 of their content. Returns match and the list of mismatches (missing, extra, size, etag or checksum), at most 1000",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare"
+                ],
+                "summary": "Verify R2 objects",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Verify R2 objects request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.VerifyR2ObjectsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/release": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List release records of the environment, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-release"
+                ],
+                "summary": "List releases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Namespace ID",
+                        "name": "namespaceid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "KV version key",
+                        "name": "keyname",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max records (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Asynchronous with sourcedir: a background job copies the build directory into the R2 version directory, verifies that every object matches the build directory (count, size and ETag) and that it is non-empty, then points the KV version key at it. 202 is returned as soon as the job is created, with release_id, job_id and the release in its initial copying status; track the final status with GET /api/v1/jobs/{id} or GET /api/v1/cloudflare/release. Synchronous without sourcedir: the existing version directory is checked, the key is switched immediately and 200 is returned with the succeeded release. The previous value is recorded for rollback. Only one release of a key can run at a time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-release"
+                ],
+                "summary": "Release static site",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Release request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReleaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Released (without sourcedir)",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "202": {
+                        "description": "Release job created (with sourcedir), data holds release_id, job_id and release",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Another release of the key is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Release failed, KV unchanged",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/release/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore the KV version key to the value recorded before a release. Refused if the key no longer points at that release",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-release"
+                ],
+                "summary": "Roll back release",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Rollback release request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RollbackReleaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/cloudflare/{accountId}/kv/namespaces": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all KV namespaces for a Cloudflare account (every page is fetched)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "cloudflare-kv"
+                ],
+                "summary": "Get KV namespaces",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List background jobs, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "List background jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "env",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "r2_copy, r2_delete, r2_move or release",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, running, succeeded, failed or cancelled",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Max jobs (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status and progress (objects and bytes done/total, failed objects and their errors) of a background job",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Get background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/jobs/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a pending or running job. Objects already processed are not rolled back",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "jobs"
+                ],
+                "summary": "Cancel background job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid job ID",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "404": {
+                        "description": "Job not found",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "409": {
+                        "description": "Job already finished",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of all Function Compute services",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List FC services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/aliases": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update service alias to point to a specific version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "aliases"
+                ],
+                "summary": "Update alias",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Update alias request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateAliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of aliases for a specific service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "aliases"
+                ],
+                "summary": "List aliases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "List alias request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListAliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/functions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of functions for a specific service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "functions"
+                ],
+                "summary": "List functions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "List fc request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListFcRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publish a service version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Publish service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Publish service request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.PublicServiceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/services/versions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get list of versions for a specific service",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List service versions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "countryCode",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "name": "env",
+                        "in": "header"
+                    },
+                    {
+                        "description": "List service version request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ListServiceVersionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request headers or body",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/model.Response"
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/system/zones": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Validate token and get user information along with available zones",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get zone information and validate token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Response with userid, username, environment, and country_code",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized or invalid token",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/healthcheck": {
+            "get": {
+                "description": "Returns OK if the service is running",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "ok",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "constants.KVBulkPair": {
+            "type": "object",
+            "properties": {
+                "base64": {
+                    "type": "boolean"
+                },
+                "expiration": {
+                    "type": "integer"
+                },
+                "expiration_ttl": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.AddPagesDomainRequest": {
+            "type": "object",
+            "required": [
+                "domainname"
+            ],
+            "properties": {
+                "domainname": {
+                    "type": "string",
+                    "example": "id.example.com"
+                }
+            }
+        },
+        "handler.BulkDeleteKVKeysRequest": {
+            "type": "object",
+            "required": [
+                "keys",
+                "namespaceid"
+            ],
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "namespaceid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.BulkUpdateKVKeyValuesRequest": {
+            "type": "object",
+            "required": [
+                "namespaceid",
+                "pairs"
+            ],
+            "properties": {
+                "namespaceid": {
+                    "type": "string"
+                },
+                "pairs": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/constants.KVBulkPair"
+                    }
+                }
+            }
+        },
+        "handler.CopyDirectoryRequest": {
+            "type": "object",
+            "required": [
+                "bucketname",
+                "sourcedir",
+                "targetdir"
+            ],
+            "properties": {
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
+                },
+                "concurrency": {
+                    "description": "为空时使用配置的并发数",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "deleteextra": {
+                    "description": "增量同步时将目标目录中多余的对象移入回收站",
+                    "type": "boolean"
+                },
+                "sourcedir": {
+                    "type": "string",
+                    "example": "path/to/source/directory"
+                },
+                "sync": {
+                    "description": "为 true 时只复制目标中不存在或大小、ETag 不同的对象",
+                    "type": "boolean"
+                },
+                "targetdir": {
+                    "type": "string",
+                    "example": "path/to/target/directory"
+                },
+                "verify": {
+                    "description": "复制完成后校验目标目录与源目录一致，不一致时任务失败",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.CopyR2ObjectsRequest": {
+            "type": "object",
+            "required": [
+                "source",
+                "target"
+            ],
+            "properties": {
+                "concurrency": {
+                    "description": "为空时使用配置的并发数",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "deleteextra": {
+                    "description": "增量同步时将目标中多余的对象移入回收站，目标前缀不能为空",
+                    "type": "boolean"
+                },
+                "source": {
+                    "$ref": "#/definitions/handler.R2LocationRequest"
+                },
+                "sync": {
+                    "description": "为 true 时只复制目标中不存在或大小、ETag 不同的对象",
+                    "type": "boolean"
+                },
+                "target": {
+                    "$ref": "#/definitions/handler.R2LocationRequest"
+                },
+                "verify": {
+                    "description": "复制完成后校验目标与源一致",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.CreateKVNamespaceRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "title": {
+                    "type": "string",
+                    "example": "site-config"
+                }
+            }
+        },
+        "handler.DeleteDirectoryRequest": {
+            "type": "object",
+            "required": [
+                "bucketname",
+                "dirpath"
+            ],
+            "properties": {
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
+                },
+                "concurrency": {
+                    "description": "为空时使用配置的并发数",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "dirpath": {
+                    "type": "string",
+                    "example": "path/to/directory"
+                },
+                "dryrun": {
+                    "description": "为 true 时只返回将删除的对象数量和总大小",
+                    "type": "boolean"
+                },
+                "hard": {
+                    "description": "为 true 时永久删除，仅管理员可用；默认移入回收站",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.DeleteKVNamespaceRequest": {
+            "type": "object",
+            "required": [
+                "namespaceid"
+            ],
+            "properties": {
+                "force": {
+                    "description": "为 true 时即使仍被 Pages 项目绑定也删除",
+                    "type": "boolean"
+                },
+                "namespaceid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.DiffKVHistoryRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "compareid": {
+                    "description": "为空时比较该版本写入前后的值",
+                    "type": "integer",
+                    "example": 2
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.GetBucketRequest": {
+            "type": "object",
+            "required": [
+                "bucketname"
+            ],
+            "properties": {
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
+                }
+            }
+        },
+        "handler.GetKVKeyValuesRequest": {
+            "type": "object",
+            "required": [
+                "keyname",
+                "namespaceid"
+            ],
+            "properties": {
+                "keyname": {
+                    "type": "string"
+                },
+                "namespaceid": {
+                    "type": "string"
+                },
+                "withmetadata": {
+                    "description": "为 true 时以 JSON 返回值、元数据和过期时间",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.GetKVKeysRequest": {
+            "type": "object",
+            "required": [
+                "namespaceid"
+            ],
+            "properties": {
+                "all": {
+                    "description": "从 cursor 开始遍历所有分页",
+                    "type": "boolean"
+                },
+                "cursor": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 10,
+                    "example": 1000
+                },
+                "namespaceid": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string",
+                    "example": "ProdVersion"
+                }
+            }
+        },
+        "handler.KVLocationRequest": {
+            "type": "object",
+            "required": [
+                "countrycode",
+                "env",
+                "namespaceid"
+            ],
+            "properties": {
+                "countrycode": {
+                    "type": "string",
+                    "example": "id"
+                },
+                "env": {
+                    "type": "string",
+                    "example": "pre"
+                },
+                "namespaceid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.KVValueSchemaRequest": {
+            "type": "object",
+            "required": [
+                "keypattern",
+                "namespaceid",
+                "rule",
+                "ruletype"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "isactive": {
+                    "type": "boolean"
+                },
+                "keypattern": {
+                    "type": "string",
+                    "example": "*ProdVersion*"
+                },
+                "namespaceid": {
+                    "type": "string",
+                    "example": "*"
+                },
+                "rule": {
+                    "type": "string",
+                    "example": "^v\\d+\\.\\d+\\.\\d+$"
+                },
+                "ruletype": {
+                    "type": "string",
+                    "enum": [
+                        "json_schema",
+                        "regex"
+                    ],
+                    "example": "regex"
+                }
+            }
+        },
+        "handler.ListAliasRequest": {
+            "type": "object",
+            "required": [
+                "servicename"
+            ],
+            "properties": {
+                "servicename": {
+                    "type": "string"
                 }
             }
         },
-        "/healthcheck": {
-            "get": {
-                "description": "Returns OK if the service is running",
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "system"
-                ],
-                "summary": "Health check endpoint",
-                "responses": {
-                    "200": {
-                        "description": "ok",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
+        "handler.ListFcRequest": {
+            "type": "object",
+            "required": [
+                "servicename"
+            ],
+            "properties": {
+                "servicename": {
+                    "type": "string"
                 }
             }
-        }
-    },
-    "definitions": {
-        "gorm.DeletedAt": {
+        },
+        "handler.ListKVHistoryRequest": {
             "type": "object",
+            "required": [
+                "keyname",
+                "namespaceid"
+            ],
             "properties": {
-                "time": {
+                "keyname": {
                     "type": "string"
                 },
-                "valid": {
-                    "description": "Valid is true if Time is not NULL",
-                    "type": "boolean"
+                "limit": {
+                    "type": "integer",
+                    "maximum": 1000,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "namespaceid": {
+                    "type": "string"
                 }
             }
         },
-        "handler.CopyDirectoryRequest": {
+        "handler.ListServiceVersionRequest": {
+            "type": "object",
+            "required": [
+                "servicename"
+            ],
+            "properties": {
+                "servicename": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PagesDeploymentConfigRequest": {
+            "type": "object",
+            "properties": {
+                "compatibilitydate": {
+                    "type": "string",
+                    "example": "2024-09-23"
+                },
+                "compatibilityflags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "d1databases": {
+                    "description": "绑定名 -\u003e 数据库 ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "envvars": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handler.PagesEnvVarRequest"
+                    }
+                },
+                "kvnamespaces": {
+                    "description": "绑定名 -\u003e 命名空间 ID",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "r2buckets": {
+                    "description": "绑定名 -\u003e 桶名称",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.PagesEnvVarRequest": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "plain_text",
+                        "secret_text"
+                    ],
+                    "example": "plain_text"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PresignR2ObjectRequest": {
             "type": "object",
             "required": [
                 "bucketname",
-                "sourcedir",
-                "targetdir"
+                "key"
             ],
             "properties": {
                 "bucketname": {
                     "type": "string",
                     "example": "bucket-name"
                 },
-                "sourcedir": {
+                "contenttype": {
+                    "description": "仅 PUT，上传时必须携带相同的 Content-Type",
+                    "type": "string"
+                },
+                "expires": {
+                    "description": "有效期（秒），0 表示使用默认值",
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 900
+                },
+                "key": {
                     "type": "string",
-                    "example": "path/to/source/directory"
+                    "example": "v1.2.3/app.zip"
                 },
-                "targetdir": {
+                "method": {
+                    "description": "默认 GET",
                     "type": "string",
-                    "example": "path/to/target/directory"
+                    "enum": [
+                        "GET",
+                        "PUT"
+                    ],
+                    "example": "GET"
                 }
             }
         },
-        "handler.DeleteDirectoryRequest": {
+        "handler.PromoteKVRequest": {
+            "type": "object",
+            "required": [
+                "source",
+                "target"
+            ],
+            "properties": {
+                "confirm": {
+                    "description": "为 false 时只返回预览",
+                    "type": "boolean"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "previewhash": {
+                    "description": "确认执行时必填，取自预览结果的 hash",
+                    "type": "string"
+                },
+                "source": {
+                    "$ref": "#/definitions/handler.KVLocationRequest"
+                },
+                "target": {
+                    "$ref": "#/definitions/handler.KVLocationRequest"
+                }
+            }
+        },
+        "handler.PublicServiceRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "servicename"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "servicename": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.R2LocationRequest": {
             "type": "object",
             "required": [
                 "bucketname",
-                "dirpath"
+                "countrycode",
+                "env"
             ],
             "properties": {
                 "bucketname": {
                     "type": "string",
                     "example": "bucket-name"
                 },
-                "dirpath": {
+                "countrycode": {
                     "type": "string",
-                    "example": "path/to/directory"
+                    "example": "id"
+                },
+                "env": {
+                    "type": "string",
+                    "example": "pre"
+                },
+                "prefix": {
+                    "description": "为空表示整个桶",
+                    "type": "string",
+                    "example": "v1.2.3/"
                 }
             }
         },
-        "handler.GetBucketRequest": {
+        "handler.R2RetentionPolicyRequest": {
             "type": "object",
             "required": [
-                "bucketname"
+                "bucketname",
+                "keep"
             ],
             "properties": {
                 "bucketname": {
                     "type": "string",
                     "example": "bucket-name"
+                },
+                "intervalhours": {
+                    "description": "定时执行间隔（小时），为空时不定时执行",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 24
+                },
+                "keep": {
+                    "description": "保留最新的版本目录数量",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
                 }
             }
         },
-        "handler.GetKVKeyValuesRequest": {
+        "handler.R2RetentionRequest": {
             "type": "object",
             "required": [
-                "keyname",
-                "namespaceid"
+                "bucketname"
             ],
             "properties": {
-                "keyname": {
-                    "type": "string"
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
                 },
-                "namespaceid": {
-                    "type": "string"
+                "keep": {
+                    "description": "为空时使用 bucket 已配置的策略",
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 10
                 }
             }
         },
-        "handler.GetKVKeysRequest": {
+        "handler.ReleaseRequest": {
             "type": "object",
             "required": [
-                "namespaceid"
+                "bucketname",
+                "keyname",
+                "namespaceid",
+                "version"
             ],
             "properties": {
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
+                },
+                "keyname": {
+                    "type": "string",
+                    "example": "ProdVersion"
+                },
                 "namespaceid": {
                     "type": "string"
+                },
+                "sourcedir": {
+                    "description": "为空时不复制，要求版本目录已存在",
+                    "type": "string",
+                    "example": "latest"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.2.3"
                 }
             }
         },
-        "handler.ListAliasRequest": {
+        "handler.RenameKVNamespaceRequest": {
             "type": "object",
             "required": [
-                "servicename"
+                "namespaceid",
+                "title"
             ],
             "properties": {
-                "servicename": {
+                "namespaceid": {
                     "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "site-config"
                 }
             }
         },
-        "handler.ListFcRequest": {
+        "handler.RestoreKVHistoryRequest": {
             "type": "object",
             "required": [
-                "servicename"
+                "id"
             ],
             "properties": {
-                "servicename": {
-                    "type": "string"
+                "before": {
+                    "description": "为 true 时恢复到该版本写入前的值",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "handler.ListServiceVersionRequest": {
+        "handler.RestoreR2TrashRequest": {
             "type": "object",
             "required": [
-                "servicename"
+                "bucketname",
+                "id"
             ],
             "properties": {
-                "servicename": {
-                    "type": "string"
+                "bucketname": {
+                    "type": "string",
+                    "example": "bucket-name"
+                },
+                "concurrency": {
+                    "description": "为空时使用配置的并发数",
+                    "type": "integer",
+                    "maximum": 200,
+                    "minimum": 1,
+                    "example": 50
+                },
+                "id": {
+                    "description": "回收站条目 ID",
+                    "type": "string",
+                    "example": "20240101T120000Z-1a2b3c4d/v1.2.3"
                 }
             }
         },
-        "handler.PublicServiceRequest": {
+        "handler.RollbackReleaseRequest": {
             "type": "object",
             "required": [
-                "description",
-                "servicename"
+                "id"
             ],
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "servicename": {
-                    "type": "string"
+                "id": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
@@ -1921,6 +6210,64 @@ const docTemplate = `{
                 "keyvalue",
                 "namespaceid"
             ],
+            "properties": {
+                "expiration": {
+                    "type": "integer",
+                    "example": 1735689600
+                },
+                "expirationttl": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "keyname": {
+                    "type": "string"
+                },
+                "keyvalue": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "type": "object"
+                },
+                "namespaceid": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.UpdatePagesProjectConfigRequest": {
+            "type": "object",
+            "properties": {
+                "buildcommand": {
+                    "type": "string",
+                    "example": "npm run build"
+                },
+                "confirm": {
+                    "description": "为 false 时只返回变更预览",
+                    "type": "boolean"
+                },
+                "destinationdir": {
+                    "type": "string"
+                },
+                "preview": {
+                    "$ref": "#/definitions/handler.PagesDeploymentConfigRequest"
+                },
+                "previewhash": {
+                    "description": "确认执行时必填，取自预览结果的 hash",
+                    "type": "string"
+                },
+                "production": {
+                    "$ref": "#/definitions/handler.PagesDeploymentConfigRequest"
+                },
+                "rootdir": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ValidateKVValueRequest": {
+            "type": "object",
+            "required": [
+                "keyname",
+                "namespaceid"
+            ],
             "properties": {
                 "keyname": {
                     "type": "string"
@@ -1933,6 +6280,21 @@ const docTemplate = `{
                 }
             }
         },
+        "handler.VerifyR2ObjectsRequest": {
+            "type": "object",
+            "required": [
+                "source",
+                "target"
+            ],
+            "properties": {
+                "source": {
+                    "$ref": "#/definitions/handler.R2LocationRequest"
+                },
+                "target": {
+                    "$ref": "#/definitions/handler.R2LocationRequest"
+                }
+            }
+        },
         "model.AliyunAccountInfo": {
             "type": "object",
             "properties": {
@@ -2023,6 +6385,10 @@ const docTemplate = `{
         },
         "model.LoginRequest": {
             "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
             "properties": {
                 "password": {
                     "type": "string"
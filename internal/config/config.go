@@ -14,9 +14,23 @@ type MysqlConfig struct {
 	Database string `json:"database"`
 }
 
+// CloudflareConfig Cloudflare 相关配置
+type CloudflareConfig struct {
+	KVKeyPatterns          []string `json:"kv_key_patterns"`           // Pages 信息接口中展示的 KV key 通配符，默认 *ProdVersion*
+	KVHistoryKeyPatterns   []string `json:"kv_history_key_patterns"`   // 批量写入和删除时只为匹配的 KV key 记录变更历史，未配置时记录所有 key
+	R2PresignDefaultExpiry int      `json:"r2_presign_default_expiry"` // R2 预签名 URL 默认有效期（秒），默认 900
+	R2PresignMaxExpiry     int      `json:"r2_presign_max_expiry"`     // R2 预签名 URL 最长有效期（秒），默认 3600
+	R2BulkConcurrency      int      `json:"r2_bulk_concurrency"`       // R2 批量复制、删除的并发数，默认 50
+	R2BulkMaxRetries       int      `json:"r2_bulk_max_retries"`       // R2 批量操作临时错误的最大重试次数，默认 3
+	R2TrashRetentionHours  int      `json:"r2_trash_retention_hours"`  // R2 回收站中目录的保留时间（小时），超过后被清理，默认 168
+	R2ArchiveMaxBytes      int64    `json:"r2_archive_max_bytes"`      // R2 归档上传的最大大小（字节），同时限制归档本身和解压后的总大小，默认 2GB
+	R2ArchiveMaxEntries    int      `json:"r2_archive_max_entries"`    // R2 归档上传最多解压的文件数，默认 50000
+}
+
 // Config 全局配置
 type Config struct {
-	Mysql MysqlConfig `json:"mysql"`
+	Mysql      MysqlConfig      `json:"mysql"`
+	Cloudflare CloudflareConfig `json:"cloudflare"`
 }
 
 var GlobalConfig Config
@@ -1,9 +1,36 @@
 package constants
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // Cloudflare API 相关常量
 const (
 	// CFBaseURL Cloudflare API 基础 URL
 	CFBaseURL = "https://api.cloudflare.com/client/v4/accounts"
+
+	// KVKeysMinLimit KV keys 列表单页最小数量
+	KVKeysMinLimit = 10
+	// KVKeysMaxLimit KV keys 列表单页最大数量
+	KVKeysMaxLimit = 1000
+
+	// KVMinExpirationTTL KV key 最小过期秒数
+	KVMinExpirationTTL = 60
+	// KVMaxMetadataSize KV key 元数据最大字节数
+	KVMaxMetadataSize = 1024
+
+	// KVBulkMaxItems KV 批量接口单次请求最大 key 数量
+	KVBulkMaxItems = 10000
+
+	// KVProdVersionKey 标识站点发布版本的 KV key
+	KVProdVersionKey = "ProdVersion"
+
+	// R2ListMaxKeys R2 对象列表单页最大数量
+	R2ListMaxKeys = 1000
+
+	// R2MultipartPartSize R2 分片上传的分片大小，超过该大小的对象使用分片上传
+	R2MultipartPartSize = 16 << 20
 )
 
 // KVNamespace 表示 KV 命名空间信息
@@ -15,7 +42,9 @@ type KVNamespace struct {
 
 // KVKeys 表示 KV keys信息
 type KVKeys struct {
-	Name string `json:"name"`
+	Name       string          `json:"name"`
+	Expiration int64           `json:"expiration,omitempty"`
+	Metadata   json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
 }
 
 // KVKeysValues 表示 KV keys值信息
@@ -33,15 +62,77 @@ type UpdateKVKeysValues struct {
 
 // CFResponse Cloudflare API 通用响应结构
 type CFResponse[T any] struct {
+	Success    bool          `json:"success"`
+	Errors     []interface{} `json:"errors"`
+	Messages   []interface{} `json:"messages"`
+	Result     []T           `json:"result"`
+	ResultInfo *CFResultInfo `json:"result_info,omitempty"`
+}
+
+// CFResultInfo Cloudflare API 分页信息
+type CFResultInfo struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	Count      int    `json:"count"`
+	TotalCount int    `json:"total_count,omitempty"`
+	TotalPages int    `json:"total_pages,omitempty"`
+	Cursor     string `json:"cursor,omitempty"`
+}
+
+// KVBulkPair 表示批量写入的 KV 键值对
+type KVBulkPair struct {
+	Key           string          `json:"key"`
+	Value         string          `json:"value"`
+	Base64        bool            `json:"base64,omitempty"`
+	Expiration    int64           `json:"expiration,omitempty"`
+	ExpirationTTL int64           `json:"expiration_ttl,omitempty"`
+	Metadata      json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+}
+
+// KVBulkWriteResult Cloudflare 批量接口返回结果
+type KVBulkWriteResult struct {
+	SuccessfulKeyCount int      `json:"successful_key_count"`
+	UnsuccessfulKeys   []string `json:"unsuccessful_keys"`
+}
+
+// KVBulkChunkResult 表示单个分批请求的执行结果
+type KVBulkChunkResult struct {
+	Index              int      `json:"index"`
+	Offset             int      `json:"offset"`
+	Size               int      `json:"size"`
+	Success            bool     `json:"success"`
+	SuccessfulKeyCount int      `json:"successful_key_count"`
+	UnsuccessfulKeys   []string `json:"unsuccessful_keys,omitempty"`
+	Error              string   `json:"error,omitempty"`
+}
+
+// KVBulkResult 表示批量操作的汇总结果
+type KVBulkResult struct {
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Chunks    []KVBulkChunkResult `json:"chunks"`
+}
+
+// CFSingleResponse Cloudflare API 单对象响应结构
+type CFSingleResponse[T any] struct {
 	Success  bool          `json:"success"`
 	Errors   []interface{} `json:"errors"`
 	Messages []interface{} `json:"messages"`
-	Result   []T           `json:"result"`
+	Result   T             `json:"result"`
 }
 
 // CFRawResponse 处理非 JSON 格式的响应
 type CFRawResponse struct {
-	RawData string
+	RawData    string
+	Expiration int64
+}
+
+// KVKeyValueDetail 表示 KV key 的值、元数据和过期时间
+type KVKeyValueDetail struct {
+	Value      string          `json:"value"`
+	Metadata   json.RawMessage `json:"metadata,omitempty" swaggertype:"object"`
+	Expiration int64           `json:"expiration,omitempty"`
 }
 
 // PagesProject 表示 Pages 项目信息
@@ -52,40 +143,202 @@ type PagesProject struct {
 	CreatedOn          string            `json:"created_on"`
 	ModifiedOn         string            `json:"modified_on"`
 	ProductionBranch   string            `json:"production_branch"`
+	Build_config       PagesBuildConfig  `json:"build_config"`
 	Deployment_configs DeploymentConfigs `json:"deployment_configs"`
 }
 
-// DeploymentConfigs 部署配置
-type DeploymentConfigs struct {
-	Production Production `json:"production"`
+// PagesBuildConfig 表示 Pages 项目构建配置
+type PagesBuildConfig struct {
+	BuildCommand   string `json:"build_command"`
+	DestinationDir string `json:"destination_dir"`
+	RootDir        string `json:"root_dir"`
 }
 
-// Production 表示生产环境配置
-type Production struct {
-	KV_namespaces KV_Namespaces `json:"kv_namespaces"`
-	R2_buckets    R2_Buckets    `json:"r2_buckets"`
+// DeploymentConfigs 部署配置
+type DeploymentConfigs struct {
+	Preview    DeploymentConfig `json:"preview"`
+	Production DeploymentConfig `json:"production"`
 }
 
-// kv_namespaces 表示 KV 命名空间信息
-type KV_Namespaces struct {
-	Kv KVInfo `json:"KV"`
+// DeploymentConfig 表示单个环境的部署配置，各类绑定以绑定名称为 key
+type DeploymentConfig struct {
+	CompatibilityDate         string                          `json:"compatibility_date,omitempty"`
+	CompatibilityFlags        []string                        `json:"compatibility_flags,omitempty"`
+	KV_namespaces             map[string]KVInfo               `json:"kv_namespaces,omitempty"`
+	R2_buckets                map[string]R2BucketBinding      `json:"r2_buckets,omitempty"`
+	D1_databases              map[string]D1DatabaseBinding    `json:"d1_databases,omitempty"`
+	Durable_object_namespaces map[string]DurableObjectBinding `json:"durable_object_namespaces,omitempty"`
+	Services                  map[string]ServiceBinding       `json:"services,omitempty"`
+	Env_vars                  map[string]EnvVarBinding        `json:"env_vars,omitempty"`
 }
 
-// namespace_id 表示 KV 命名空间 ID
+// KVInfo 表示 KV 命名空间绑定，Keys 为命名空间中匹配 key 规则的 key
 type KVInfo struct {
 	Namespace_ID        string   `json:"namespace_id"`
 	Title               string   `json:"title,omitempty"`
 	SupportsURLEncoding bool     `json:"supports_url_encoding,omitempty"`
 	Keys                []KVKeys `json:"keys,omitempty"`
-	HasProdVersion      bool     `json:"has_prod_version,omitempty"`
+	HasProdVersion      bool     `json:"has_prod_version,omitempty"` // 存在匹配 key 规则的 key
+}
+
+// R2BucketBinding 表示 R2 桶绑定
+type R2BucketBinding struct {
+	Name         string `json:"name"`
+	Jurisdiction string `json:"jurisdiction,omitempty"`
+}
+
+// D1DatabaseBinding 表示 D1 数据库绑定
+type D1DatabaseBinding struct {
+	ID string `json:"id"`
+}
+
+// DurableObjectBinding 表示 Durable Object 命名空间绑定
+type DurableObjectBinding struct {
+	Namespace_ID string `json:"namespace_id"`
+}
+
+// ServiceBinding 表示 Worker 服务绑定
+type ServiceBinding struct {
+	Service     string `json:"service"`
+	Environment string `json:"environment,omitempty"`
+	Entrypoint  string `json:"entrypoint,omitempty"`
+}
+
+// EnvVarBinding 表示环境变量，secret_text 类型的值不会返回
+type EnvVarBinding struct {
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+// PagesDeployment 表示 Pages 部署信息
+type PagesDeployment struct {
+	ID                string                 `json:"id"`
+	ShortID           string                 `json:"short_id"`
+	ProjectName       string                 `json:"project_name"`
+	Environment       string                 `json:"environment"`
+	URL               string                 `json:"url"`
+	CreatedOn         string                 `json:"created_on"`
+	ModifiedOn        string                 `json:"modified_on"`
+	Aliases           []string               `json:"aliases"`
+	LatestStage       PagesDeploymentStage   `json:"latest_stage"`
+	Stages            []PagesDeploymentStage `json:"stages,omitempty"`
+	DeploymentTrigger PagesDeploymentTrigger `json:"deployment_trigger"`
+	IsSkippedBuild    bool                   `json:"is_skipped_build"`
+}
+
+// PagesDeploymentStage 表示部署阶段及状态
+type PagesDeploymentStage struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	StartedOn string `json:"started_on,omitempty"`
+	EndedOn   string `json:"ended_on,omitempty"`
+}
+
+// PagesDeploymentTrigger 表示部署触发信息
+type PagesDeploymentTrigger struct {
+	Type     string                         `json:"type"`
+	Metadata PagesDeploymentTriggerMetadata `json:"metadata"`
+}
+
+// PagesDeploymentTriggerMetadata 表示触发部署的分支和提交
+type PagesDeploymentTriggerMetadata struct {
+	Branch        string `json:"branch"`
+	CommitHash    string `json:"commit_hash"`
+	CommitMessage string `json:"commit_message"`
+	CommitDirty   bool   `json:"commit_dirty,omitempty"`
+}
+
+// PagesDeploymentLogs 表示部署构建日志
+type PagesDeploymentLogs struct {
+	Total                 int                  `json:"total"`
+	IncludesContainerLogs bool                 `json:"includes_container_logs"`
+	Data                  []PagesDeploymentLog `json:"data"`
+}
+
+// PagesDeploymentLog 表示一行构建日志
+type PagesDeploymentLog struct {
+	Ts   string `json:"ts"`
+	Line string `json:"line"`
+}
+
+// PagesDomain 表示 Pages 项目自定义域名
+type PagesDomain struct {
+	ID                   string                `json:"id"`
+	Name                 string                `json:"name"`
+	Status               string                `json:"status"` // initializing、pending、active、deactivated、blocked、error
+	ZoneTag              string                `json:"zone_tag,omitempty"`
+	CertificateAuthority string                `json:"certificate_authority,omitempty"`
+	CreatedOn            string                `json:"created_on"`
+	VerificationData     PagesDomainVerify     `json:"verification_data"`
+	ValidationData       PagesDomainValidation `json:"validation_data"`
+}
+
+// PagesDomainVerify 表示域名所有权验证状态
+type PagesDomainVerify struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// PagesDomainValidation 表示证书验证状态
+type PagesDomainValidation struct {
+	Status       string `json:"status"`
+	Method       string `json:"method,omitempty"`
+	TxtName      string `json:"txt_name,omitempty"`
+	TxtValue     string `json:"txt_value,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// R2Object 表示 R2 对象信息
+type R2Object struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"last_modified"`
+	StorageClass string    `json:"storage_class,omitempty"`
+}
+
+// R2Folder 表示按分隔符归并的目录
+type R2Folder struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"` // 相对当前前缀的目录名
+}
+
+// R2ListResult 表示一页 R2 对象列表
+type R2ListResult struct {
+	Bucket                string     `json:"bucket"`
+	Prefix                string     `json:"prefix"`
+	Delimiter             string     `json:"delimiter"`
+	Folders               []R2Folder `json:"folders"`
+	Objects               []R2Object `json:"objects"`
+	KeyCount              int32      `json:"key_count"`
+	IsTruncated           bool       `json:"is_truncated"`
+	NextContinuationToken string     `json:"next_continuation_token,omitempty"`
+}
+
+// R2UploadedObject 表示上传完成的 R2 对象
+type R2UploadedObject struct {
+	Key         string `json:"key"`
+	Size        int64  `json:"size"`
+	ContentType string `json:"content_type"`
+	ETag        string `json:"etag"`
+	Multipart   bool   `json:"multipart"`
 }
 
-// r2_buckets 表示 R2 桶信息
-type R2_Buckets struct {
-	R2_Assets R2_ASSETS `json:"R2_ASSETS"`
+// R2UploadResult 表示一次上传的结果，归档上传时包含解压出的所有对象
+type R2UploadResult struct {
+	Bucket    string             `json:"bucket"`
+	Prefix    string             `json:"prefix,omitempty"`
+	Count     int                `json:"count"`
+	TotalSize int64              `json:"total_size"`
+	Objects   []R2UploadedObject `json:"objects"`
 }
 
-// R2_ASSETS 表示 R2 桶信息
-type R2_ASSETS struct {
-	R2_Name string `json:"name"`
+// R2PresignedURL 表示 R2 预签名 URL
+type R2PresignedURL struct {
+	Bucket    string              `json:"bucket"`
+	Key       string              `json:"key"`
+	Method    string              `json:"method"`
+	URL       string              `json:"url"`
+	Headers   map[string][]string `json:"headers,omitempty"` // 使用 URL 时必须携带的请求头
+	ExpiresAt time.Time           `json:"expires_at"`
 }
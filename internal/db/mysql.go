@@ -30,7 +30,7 @@ func InitDB() error {
 	}
 
 	// 自动迁移数据库结构，创建表
-	err = DB.AutoMigrate(&AliasRecord{}, &model.User{}, &model.UserSession{}, &model.AliyunAccountInfo{}, &model.EnvironmentConfig{}, &model.CloudflareAccountInfo{})
+	err = DB.AutoMigrate(&AliasRecord{}, &model.User{}, &model.UserSession{}, &model.AliyunAccountInfo{}, &model.EnvironmentConfig{}, &model.CloudflareAccountInfo{}, &model.KVValueHistory{}, &model.KVValueSchema{}, &model.ReleaseRecord{}, &model.Job{}, &model.R2RetentionPolicy{})
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %v", err)
 	}
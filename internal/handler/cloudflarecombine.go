@@ -5,18 +5,20 @@ import (
 	"openapi/internal/logger"
 	"openapi/internal/middleware"
 	"openapi/internal/service"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 // GetPagesProjectHandlerInfo godoc
 // @Summary      Get Pages projects
-// @Description  Get list of Cloudflare Pages projects
+// @Description  Get Cloudflare Pages projects with every preview and production binding; KV bindings include the keys matching the configured key patterns
 // @Tags         cloudflare-pages
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        keypatterns     query   string  false  "Comma separated key patterns overriding the configured ones, e.g. *ProdVersion*,*Config"
 // @Success      200  {object}  model.Response
 // @Failure      400  {object}  model.Response  "Invalid request headers"
 // @Failure      401  {object}  model.Response  "Unauthorized"
@@ -33,8 +35,15 @@ func GetPagesProjectHandlerInfo(c *gin.Context) {
 		return
 	}
 
+	var keyPatterns []string
+	for _, pattern := range strings.Split(c.Query("keypatterns"), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			keyPatterns = append(keyPatterns, pattern)
+		}
+	}
+
 	logger.Info("Country-Code: %s, Env: %s", headers.CountryCode, headers.Env)
-	resp, err := service.GetPagesProjectWithKVNamespacesAndKeys(headers.CountryCode, headers.Env)
+	resp, err := service.GetPagesProjectWithKVNamespacesAndKeys(headers.CountryCode, headers.Env, keyPatterns)
 	if err != nil {
 		logger.Error("Failed to get pages project: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkUpdateKVKeyValuesRequest 批量写入 KV 的请求结构
+type BulkUpdateKVKeyValuesRequest struct {
+	NameSpaceId string                 `json:"namespaceid" binding:"required"`
+	Pairs       []constants.KVBulkPair `json:"pairs" binding:"required,min=1"`
+}
+
+// BulkDeleteKVKeysRequest 批量删除 KV 的请求结构
+type BulkDeleteKVKeysRequest struct {
+	NameSpaceId string   `json:"namespaceid" binding:"required"`
+	Keys        []string `json:"keys" binding:"required,min=1"`
+}
+
+// BulkUpdateKVKeyValuesHandler godoc
+// @Summary      Bulk update KV key values
+// @Description  Write many key-value pairs to a Cloudflare KV namespace, split into chunks of 10,000 pairs. Previous values are read chunk by chunk and history is recorded for every key, or only for keys matching kv_history_key_patterns when it is configured
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    BulkUpdateKVKeyValuesRequest  true  "Bulk update KV key values request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, value failed schema validation, or too many keys to record history for"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error or some chunks failed"
+// @Router       /api/v1/cloudflare/kv/namespaces/bulk [put]
+func BulkUpdateKVKeyValuesHandler(c *gin.Context) {
+	var req BulkUpdateKVKeyValuesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Bulk updating %d KV pairs for CountryCode: %s, Env: %s, NamespaceId: %s",
+		len(req.Pairs), headers.CountryCode, headers.Env, req.NameSpaceId)
+
+	resp, err := service.BulkWriteKVKeyValues(headers.CountryCode, headers.Env, req.NameSpaceId, req.Pairs, c.GetString("username"))
+	if err != nil {
+		handleKVWriteError(c, "bulk update KV key values", err)
+		return
+	}
+
+	respondKVBulkResult(c, resp)
+}
+
+// BulkDeleteKVKeysHandler godoc
+// @Summary      Bulk delete KV keys
+// @Description  Delete many keys from a Cloudflare KV namespace, split into chunks of 10,000 keys. Previous values are read chunk by chunk and history is recorded for every key, or only for keys matching kv_history_key_patterns when it is configured
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    BulkDeleteKVKeysRequest  true  "Bulk delete KV keys request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, or too many keys to record history for"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error or some chunks failed"
+// @Router       /api/v1/cloudflare/kv/namespaces/bulk [delete]
+func BulkDeleteKVKeysHandler(c *gin.Context) {
+	var req BulkDeleteKVKeysRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Bulk deleting %d KV keys for CountryCode: %s, Env: %s, NamespaceId: %s",
+		len(req.Keys), headers.CountryCode, headers.Env, req.NameSpaceId)
+
+	resp, err := service.BulkDeleteKVKeys(headers.CountryCode, headers.Env, req.NameSpaceId, req.Keys, c.GetString("username"))
+	if err != nil {
+		handleKVWriteError(c, "bulk delete KV keys", err)
+		return
+	}
+
+	respondKVBulkResult(c, resp)
+}
+
+// respondKVBulkResult 返回批量操作结果，存在失败批次时返回 500 并附带各批次明细
+func respondKVBulkResult(c *gin.Context, result *constants.KVBulkResult) {
+	if result.Failed > 0 {
+		logger.Error("KV bulk operation finished with %d/%d failed keys", result.Failed, result.Total)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Some chunks failed",
+			"data":    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    result,
+	})
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportKVNamespaceHandler godoc
+// @Summary      Export KV namespace
+// @Description  Stream every key of a KV namespace with its value, metadata and expiration. Non UTF-8 values are base64 encoded. X-KV-Key-Count is the number of listed keys; the file ends with a trailer holding the exported count (the last NDJSON line {"trailer":{...}}, or the trailer field in JSON). If reading values fails mid-stream the trailer carries the error, and import rejects files whose trailer is missing, failed or does not match
+// @Tags         cloudflare-kv
+// @Produce      json
+// @Produce      application/x-ndjson
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        namespaceid   path    string  true   "Namespace ID"
+// @Param        format        query   string  false  "ndjson (default) or json"
+// @Success      200  {file}    file
+// @Failure      400  {object}  model.Response  "Invalid request headers or format"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces/{namespaceid}/export [get]
+func ExportKVNamespaceHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	namespaceId := c.Param("namespaceid")
+	format := c.DefaultQuery("format", service.KVExportFormatNDJSON)
+	if format != service.KVExportFormatNDJSON && format != service.KVExportFormatJSON {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "format must be ndjson or json",
+		})
+		return
+	}
+
+	logger.Info("Exporting KV namespace %s for CountryCode: %s, Env: %s as %s",
+		namespaceId, headers.CountryCode, headers.Env, format)
+
+	export, err := service.PrepareKVExport(headers.CountryCode, headers.Env, namespaceId, format)
+	if err != nil {
+		handleCloudflareError(c, "export KV namespace", err)
+		return
+	}
+
+	contentType := "application/x-ndjson"
+	if format == service.KVExportFormatJSON {
+		contentType = "application/json"
+	}
+	filename := fmt.Sprintf("kv-%s-%s.%s", namespaceId, time.Now().Format("20060102150405"), format)
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("X-KV-Key-Count", strconv.Itoa(export.Count()))
+	c.Status(http.StatusOK)
+
+	// 响应已开始输出，出错时由结束记录标明文件不完整，这里只记录日志
+	if _, err := export.WriteTo(c.Writer); err != nil {
+		logger.Error("Failed to export KV namespace %s: %v", namespaceId, err)
+	}
+}
+
+// ImportKVNamespaceHandler godoc
+// @Summary      Import KV namespace
+// @Description  Restore a KV namespace from an NDJSON or JSON export, uploaded as multipart field "file" or as the raw request body. Files without a valid trailer (truncated or failed exports) are rejected
+// @Tags         cloudflare-kv
+// @Accept       multipart/form-data
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        namespaceid   path      string  true   "Namespace ID"
+// @Param        mode          query     string  false  "skip, overwrite or fail (default)"
+// @Param        file          formData  file    false  "Export file"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers, mode or file, or incomplete export file"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      409  {object}  model.Response  "Keys already exist (fail mode)"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces/{namespaceid}/import [post]
+func ImportKVNamespaceHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	namespaceId := c.Param("namespaceid")
+	mode := c.DefaultQuery("mode", service.KVImportModeFail)
+	if mode != service.KVImportModeSkip && mode != service.KVImportModeOverwrite && mode != service.KVImportModeFail {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "mode must be skip, overwrite or fail",
+		})
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "Invalid file",
+				"error":   err.Error(),
+			})
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "Invalid file",
+				"error":   err.Error(),
+			})
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	logger.Info("Importing KV namespace %s for CountryCode: %s, Env: %s (mode: %s) by %s",
+		namespaceId, headers.CountryCode, headers.Env, mode, c.GetString("username"))
+
+	result, err := service.ImportKVNamespace(headers.CountryCode, headers.Env, namespaceId, reader, mode, c.GetString("username"))
+	var conflictErr *service.KVImportConflictError
+	if errors.As(err, &conflictErr) {
+		logger.Error("Refused to import KV namespace: %v", err)
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Keys already exist in namespace",
+			"error":   err.Error(),
+			"data":    conflictErr,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrKVExportIncomplete) {
+		logger.Error("Refused to import KV namespace: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Export file is incomplete",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		handleKVWriteError(c, "import KV namespace", err)
+		return
+	}
+
+	if result.Write != nil && result.Write.Failed > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Some keys failed to import",
+			"data":    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    result,
+	})
+}
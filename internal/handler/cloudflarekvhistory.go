@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListKVHistoryRequest 获取 KV 变更历史的请求结构
+type ListKVHistoryRequest struct {
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+	KeyName     string `json:"keyname" binding:"required"`
+	Limit       int    `json:"limit" binding:"omitempty,min=1,max=1000" example:"50"`
+}
+
+// DiffKVHistoryRequest 比较 KV 历史版本的请求结构
+type DiffKVHistoryRequest struct {
+	ID        uint `json:"id" binding:"required" example:"1"`
+	CompareID uint `json:"compareid" example:"2"` // 为空时比较该版本写入前后的值
+}
+
+// RestoreKVHistoryRequest 恢复 KV 历史版本的请求结构
+type RestoreKVHistoryRequest struct {
+	ID     uint `json:"id" binding:"required" example:"1"`
+	Before bool `json:"before"` // 为 true 时恢复到该版本写入前的值
+}
+
+// ListKVHistoryHandler godoc
+// @Summary      List KV key history
+// @Description  List recorded value changes of a Cloudflare KV key in the country and env of the request headers, newest first
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    ListKVHistoryRequest  true  "List KV history request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces/keys/history [post]
+func ListKVHistoryHandler(c *gin.Context) {
+	var req ListKVHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	records, err := service.ListKVHistory(headers.CountryCode, headers.Env, req.NameSpaceId, req.KeyName, req.Limit)
+	if err != nil {
+		logger.Error("Failed to list KV history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to list KV history",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    records,
+	})
+}
+
+// DiffKVHistoryHandler godoc
+// @Summary      Diff KV key history
+// @Description  Line diff between two revisions of a KV key, or between the old and new value of one revision. Revisions from another country or env are not found
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    DiffKVHistoryRequest  true  "Diff KV history request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces/keys/history/diff [post]
+func DiffKVHistoryHandler(c *gin.Context) {
+	var req DiffKVHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	diff, err := service.DiffKVHistory(headers.CountryCode, headers.Env, req.ID, req.CompareID)
+	if err != nil {
+		logger.Error("Failed to diff KV history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to diff KV history",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    diff,
+	})
+}
+
+// RestoreKVHistoryHandler godoc
+// @Summary      Restore KV key history
+// @Description  Restore a KV key to the value, metadata and expiration written by a revision (or those before it); only revisions of the request country and env can be restored, and the restore itself is recorded in history
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    RestoreKVHistoryRequest  true  "Restore KV history request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces/keys/history/restore [post]
+func RestoreKVHistoryHandler(c *gin.Context) {
+	var req RestoreKVHistoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Restoring KV history %d (before: %t) for CountryCode: %s, Env: %s",
+		req.ID, req.Before, headers.CountryCode, headers.Env)
+
+	record, err := service.RestoreKVHistory(headers.CountryCode, headers.Env, req.ID, req.Before, c.GetString("username"))
+	if err != nil {
+		handleKVWriteError(c, "restore KV history", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    record,
+	})
+}
@@ -11,11 +11,15 @@ import (
 
 type GetKVKeysRequest struct {
 	NameSpaceId string `json:"namespaceid" binding:"required"`
+	Prefix      string `json:"prefix" example:"ProdVersion"`
+	Limit       int    `json:"limit" binding:"omitempty,min=10,max=1000" example:"1000"`
+	Cursor      string `json:"cursor"`
+	All         bool   `json:"all"` // 从 cursor 开始遍历所有分页
 }
 
 // GetKVKeysHandler godoc
 // @Summary      Get KV namespace keys
-// @Description  Get list of keys in a Cloudflare KV namespace
+// @Description  Get list of keys in a Cloudflare KV namespace, with prefix filter and cursor pagination (set all=true to walk every page)
 // @Tags         cloudflare-kv
 // @Accept       json
 // @Produce      json
@@ -48,10 +52,15 @@ func GetKVKeysHandler(c *gin.Context) {
 		return
 	}
 
-	logger.Info("Getting KV keys for CountryCode: %s, Env: %s, Namespace: %s",
-		headers.CountryCode, headers.Env, req.NameSpaceId)
+	logger.Info("Getting KV keys for CountryCode: %s, Env: %s, Namespace: %s, Prefix: %s",
+		headers.CountryCode, headers.Env, req.NameSpaceId, req.Prefix)
 
-	resp, err := service.GetKVKeys(headers.CountryCode, headers.Env, req.NameSpaceId)
+	resp, err := service.GetKVKeys(headers.CountryCode, headers.Env, req.NameSpaceId, service.KVKeysListOptions{
+		Prefix: req.Prefix,
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+		All:    req.All,
+	})
 	if err != nil {
 		logger.Error("Failed to get KV keys: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"encoding/json"
 	"net/http"
 
+	"openapi/internal/constants"
 	"openapi/internal/logger"
 	"openapi/internal/middleware"
 	"openapi/internal/service"
@@ -11,19 +13,23 @@ import (
 )
 
 type GetKVKeyValuesRequest struct {
-	NameSpaceId string `json:"namespaceid" binding:"required"`
-	KeyName     string `json:"keyname" binding:"required"`
+	NameSpaceId  string `json:"namespaceid" binding:"required"`
+	KeyName      string `json:"keyname" binding:"required"`
+	WithMetadata bool   `json:"withmetadata"` // 为 true 时以 JSON 返回值、元数据和过期时间
 }
 
 type UpdateKVKeyValuesRequest struct {
-	NameSpaceId string `json:"namespaceid" binding:"required"`
-	KeyName     string `json:"keyname" binding:"required"`
-	KeyValue    string `json:"keyvalue" binding:"required"`
+	NameSpaceId   string          `json:"namespaceid" binding:"required"`
+	KeyName       string          `json:"keyname" binding:"required"`
+	KeyValue      string          `json:"keyvalue" binding:"required"`
+	Metadata      json.RawMessage `json:"metadata" swaggertype:"object"`
+	Expiration    int64           `json:"expiration" example:"1735689600"`
+	ExpirationTTL int64           `json:"expirationttl" example:"3600"`
 }
 
 // GetKVKeyValuesHandler godoc
 // @Summary      Get KV namespace key values
-// @Description  Get values for keys in a Cloudflare KV namespace
+// @Description  Get values for keys in a Cloudflare KV namespace; set withmetadata=true to get value, metadata and expiration as JSON
 // @Tags         cloudflare-kv
 // @Accept       json
 // @Produce      json
@@ -65,12 +71,31 @@ func GetKVKeyValuesHandler(c *gin.Context) {
 		return
 	}
 
-	c.String(http.StatusOK, resp.RawData)
+	if !req.WithMetadata {
+		c.String(http.StatusOK, resp.RawData)
+		return
+	}
+
+	metadata, err := service.GetKVKeyMetadata(headers.CountryCode, headers.Env, req.NameSpaceId, req.KeyName)
+	if err != nil {
+		handleCloudflareError(c, "get KV key metadata", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data": constants.KVKeyValueDetail{
+			Value:      resp.RawData,
+			Metadata:   metadata,
+			Expiration: resp.Expiration,
+		},
+	})
 }
 
 // UpdateKVKeyValuesHandler godoc
 // @Summary      Update KV namespace key values
-// @Description  Update values for keys in a Cloudflare KV namespace
+// @Description  Update values for keys in a Cloudflare KV namespace, optionally with metadata and expiration. The previous value is recorded in the KV history; ProdVersion keys are tagged with updated_by/updated_at metadata.
 // @Tags         cloudflare-kv
 // @Accept       json
 // @Produce      json
@@ -78,7 +103,7 @@ func GetKVKeyValuesHandler(c *gin.Context) {
 // @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
 // @Param        request       body    UpdateKVKeyValuesRequest  true  "Update KV key values request"
 // @Success      200  {object}  model.Response
-// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, or value failed schema validation"
 // @Failure      401  {object}  model.Response  "Unauthorized"
 // @Failure      500  {object}  model.Response  "Server error"
 // @Router       /api/v1/cloudflare/kv/namespaces/keys/values [put]
@@ -106,9 +131,14 @@ func UpdateKVKeyValuesHandler(c *gin.Context) {
 	logger.Info("Updating KV key value for CountryCode: %s, Env: %s, NamespaceId: %s, KeyName: %s, KeyValue: %s",
 		headers.CountryCode, headers.Env, req.NameSpaceId, req.KeyName, req.KeyValue)
 
-	resp, err := service.UpdateKVKeyValues(headers.CountryCode, headers.Env, req.NameSpaceId, req.KeyName, req.KeyValue)
+	resp, err := service.UpdateKVKeyValues(headers.CountryCode, headers.Env, req.NameSpaceId, req.KeyName, req.KeyValue, service.KVWriteOptions{
+		Metadata:      req.Metadata,
+		Expiration:    req.Expiration,
+		ExpirationTTL: req.ExpirationTTL,
+		Operator:      c.GetString("username"),
+	})
 	if err != nil {
-		handleCloudflareError(c, "update KV key value", err)
+		handleKVWriteError(c, "update KV key value", err)
 		return
 	}
 
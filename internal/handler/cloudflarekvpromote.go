@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"openapi/internal/logger"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KVLocationRequest 表示某个环境下的 KV 命名空间
+type KVLocationRequest struct {
+	Env         string `json:"env" binding:"required" example:"pre"`
+	CountryCode string `json:"countrycode" binding:"required" example:"id"`
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+}
+
+// toLocation 转换为服务层结构
+func (r KVLocationRequest) toLocation() service.KVLocation {
+	return service.KVLocation{
+		Env:         r.Env,
+		CountryCode: r.CountryCode,
+		NamespaceID: r.NameSpaceId,
+	}
+}
+
+// PromoteKVRequest 同步 KV key 的请求结构
+type PromoteKVRequest struct {
+	Source      KVLocationRequest `json:"source" binding:"required"`
+	Target      KVLocationRequest `json:"target" binding:"required"`
+	Keys        []string          `json:"keys"`
+	Prefix      string            `json:"prefix"`
+	Confirm     bool              `json:"confirm"`     // 为 false 时只返回预览
+	PreviewHash string            `json:"previewhash"` // 确认执行时必填，取自预览结果的 hash
+}
+
+// PromoteKVHandler godoc
+// @Summary      Promote KV keys
+// @Description  Copy selected keys or a key prefix from a source namespace/environment to a target. Without confirm a diff preview is returned; with confirm and the preview hash the changes are applied, keeping the source metadata and expiration (keys whose source value is about to expire fail)
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        request        body    PromoteKVRequest  true  "Promote KV request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      409  {object}  model.Response  "Source or target changed since preview"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/promote [post]
+func PromoteKVHandler(c *gin.Context) {
+	var req PromoteKVRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if len(req.Keys) == 0 && req.Prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "keys or prefix is required",
+		})
+		return
+	}
+	if req.Confirm && req.PreviewHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "previewhash is required when confirm is true",
+		})
+		return
+	}
+
+	source, target := req.Source.toLocation(), req.Target.toLocation()
+	if source == target {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "source and target are the same",
+		})
+		return
+	}
+
+	if !req.Confirm {
+		plan, err := service.PreviewKVPromotion(source, target, req.Keys, req.Prefix)
+		if err != nil {
+			handleCloudflareError(c, "preview KV promotion", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    200,
+			"message": "Success",
+			"data":    plan,
+		})
+		return
+	}
+
+	logger.Info("Promoting KV keys from %s/%s/%s to %s/%s/%s by %s",
+		source.Env, source.CountryCode, source.NamespaceID,
+		target.Env, target.CountryCode, target.NamespaceID, c.GetString("username"))
+
+	plan, err := service.ApplyKVPromotion(source, target, req.Keys, req.Prefix, req.PreviewHash, c.GetString("username"))
+	if errors.Is(err, service.ErrKVPromotionChanged) {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Source or target changed since preview, please review the new preview",
+			"error":   err.Error(),
+			"data":    plan,
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "promote KV keys", err)
+		return
+	}
+
+	if plan.Failed > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Some keys failed to promote",
+			"data":    plan,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    plan,
+	})
+}
@@ -0,0 +1,266 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"openapi/internal/logger"
+	"openapi/internal/model"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// KVValueSchemaRequest 创建或更新 KV 值校验规则的请求结构
+type KVValueSchemaRequest struct {
+	NameSpaceId string `json:"namespaceid" binding:"required" example:"*"`
+	KeyPattern  string `json:"keypattern" binding:"required" example:"*ProdVersion*"`
+	RuleType    string `json:"ruletype" binding:"required,oneof=json_schema regex" example:"regex"`
+	Rule        string `json:"rule" binding:"required" example:"^v\\d+\\.\\d+\\.\\d+$"`
+	Description string `json:"description"`
+	IsActive    *bool  `json:"isactive"`
+}
+
+// ValidateKVValueRequest 校验 KV 值的请求结构
+type ValidateKVValueRequest struct {
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+	KeyName     string `json:"keyname" binding:"required"`
+	KeyValue    string `json:"keyvalue"`
+}
+
+// toModel 转换为数据库模型，未指定 isactive 时默认启用
+func (r *KVValueSchemaRequest) toModel() *model.KVValueSchema {
+	isActive := true
+	if r.IsActive != nil {
+		isActive = *r.IsActive
+	}
+	return &model.KVValueSchema{
+		NamespaceID: r.NameSpaceId,
+		KeyPattern:  r.KeyPattern,
+		RuleType:    r.RuleType,
+		Rule:        r.Rule,
+		Description: r.Description,
+		IsActive:    isActive,
+	}
+}
+
+// ListKVValueSchemasHandler godoc
+// @Summary      List KV value schemas
+// @Description  List validation rules for KV values, optionally filtered by namespace
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        namespaceid    query   string  false  "Namespace ID"
+// @Success      200  {object}  model.Response
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/schemas [get]
+func ListKVValueSchemasHandler(c *gin.Context) {
+	schemas, err := service.ListKVValueSchemas(c.Query("namespaceid"))
+	if err != nil {
+		logger.Error("Failed to list KV value schemas: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to list KV value schemas",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    schemas,
+	})
+}
+
+// CreateKVValueSchemaHandler godoc
+// @Summary      Create KV value schema
+// @Description  Register a JSON Schema or regex rule for KV values matching a namespace and key pattern
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        request        body    KVValueSchemaRequest  true  "KV value schema"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body or rule"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/schemas [post]
+func CreateKVValueSchemaHandler(c *gin.Context) {
+	var req KVValueSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	schema := req.toModel()
+	if err := service.CreateKVValueSchema(schema); err != nil {
+		logger.Error("Failed to create KV value schema: %v", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrInvalidKVValueSchema) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": "Failed to create KV value schema",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    schema,
+	})
+}
+
+// UpdateKVValueSchemaHandler godoc
+// @Summary      Update KV value schema
+// @Description  Update a KV value validation rule
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        id             path    int     true  "Schema ID"
+// @Param        request        body    KVValueSchemaRequest  true  "KV value schema"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body or rule"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Schema not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/schemas/{id} [put]
+func UpdateKVValueSchemaHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid schema ID",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	var req KVValueSchemaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := service.UpdateKVValueSchema(uint(id), req.toModel()); err != nil {
+		logger.Error("Failed to update KV value schema %d: %v", id, err)
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrInvalidKVValueSchema):
+			status = http.StatusBadRequest
+		case errors.Is(err, service.ErrKVValueSchemaNotFound):
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": "Failed to update KV value schema",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
+
+// DeleteKVValueSchemaHandler godoc
+// @Summary      Delete KV value schema
+// @Description  Delete a KV value validation rule
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        id             path    int     true  "Schema ID"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid schema ID"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Schema not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/schemas/{id} [delete]
+func DeleteKVValueSchemaHandler(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid schema ID",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := service.DeleteKVValueSchema(uint(id)); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrKVValueSchemaNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"code":    status,
+			"message": "Failed to delete KV value schema",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
+
+// ValidateKVValueHandler godoc
+// @Summary      Validate KV value
+// @Description  Check a value against the registered rules for a namespace and key without writing it
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        request        body    ValidateKVValueRequest  true  "Validate KV value request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body or value failed validation"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/schemas/validate [post]
+func ValidateKVValueHandler(c *gin.Context) {
+	var req ValidateKVValueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	if err := service.ValidateKVValue(req.NameSpaceId, req.KeyName, req.KeyValue); err != nil {
+		handleKVWriteError(c, "validate KV value", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
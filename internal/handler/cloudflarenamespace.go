@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"openapi/internal/logger"
@@ -10,9 +11,26 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// CreateKVNamespaceRequest 创建 KV 命名空间的请求结构
+type CreateKVNamespaceRequest struct {
+	Title string `json:"title" binding:"required" example:"site-config"`
+}
+
+// RenameKVNamespaceRequest 重命名 KV 命名空间的请求结构
+type RenameKVNamespaceRequest struct {
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+	Title       string `json:"title" binding:"required" example:"site-config"`
+}
+
+// DeleteKVNamespaceRequest 删除 KV 命名空间的请求结构
+type DeleteKVNamespaceRequest struct {
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+	Force       bool   `json:"force"` // 为 true 时即使仍被 Pages 项目绑定也删除
+}
+
 // GetKVNamespacesHandler godoc
 // @Summary      Get KV namespaces
-// @Description  Get list of KV namespaces for a Cloudflare account
+// @Description  Get all KV namespaces for a Cloudflare account (every page is fetched)
 // @Tags         cloudflare-kv
 // @Accept       json
 // @Produce      json
@@ -52,3 +70,165 @@ func GetKVNamespacesHandler(c *gin.Context) {
 		"data":    resp,
 	})
 }
+
+// CreateKVNamespaceHandler godoc
+// @Summary      Create KV namespace
+// @Description  Create a KV namespace in the Cloudflare account
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    CreateKVNamespaceRequest  true  "Create KV namespace request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces [post]
+func CreateKVNamespaceHandler(c *gin.Context) {
+	var req CreateKVNamespaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Creating KV namespace %s for CountryCode: %s, Env: %s by %s",
+		req.Title, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	namespace, err := service.CreateKVNamespace(headers.CountryCode, headers.Env, req.Title)
+	if err != nil {
+		handleCloudflareError(c, "create KV namespace", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    namespace,
+	})
+}
+
+// RenameKVNamespaceHandler godoc
+// @Summary      Rename KV namespace
+// @Description  Change the title of a KV namespace
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    RenameKVNamespaceRequest  true  "Rename KV namespace request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces [put]
+func RenameKVNamespaceHandler(c *gin.Context) {
+	var req RenameKVNamespaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Renaming KV namespace %s to %s for CountryCode: %s, Env: %s by %s",
+		req.NameSpaceId, req.Title, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	if err := service.RenameKVNamespace(headers.CountryCode, headers.Env, req.NameSpaceId, req.Title); err != nil {
+		handleCloudflareError(c, "rename KV namespace", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
+
+// DeleteKVNamespaceHandler godoc
+// @Summary      Delete KV namespace
+// @Description  Delete a KV namespace. Refused while a Pages project still binds it unless force is set
+// @Tags         cloudflare-kv
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    DeleteKVNamespaceRequest  true  "Delete KV namespace request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      409  {object}  model.Response  "Namespace is still bound by Pages projects"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/kv/namespaces [delete]
+func DeleteKVNamespaceHandler(c *gin.Context) {
+	var req DeleteKVNamespaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Deleting KV namespace %s for CountryCode: %s, Env: %s by %s (force: %t)",
+		req.NameSpaceId, headers.CountryCode, headers.Env, c.GetString("username"), req.Force)
+
+	err := service.DeleteKVNamespace(headers.CountryCode, headers.Env, req.NameSpaceId, req.Force)
+	var inUseErr *service.KVNamespaceInUseError
+	if errors.As(err, &inUseErr) {
+		logger.Error("Refused to delete KV namespace: %v", err)
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Namespace is still bound by Pages projects",
+			"error":   err.Error(),
+			"data":    inUseErr,
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "delete KV namespace", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
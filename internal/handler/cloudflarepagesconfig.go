@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PagesEnvVarRequest 环境变量
+type PagesEnvVarRequest struct {
+	Type  string `json:"type" binding:"omitempty,oneof=plain_text secret_text" example:"plain_text"`
+	Value string `json:"value"`
+}
+
+// PagesDeploymentConfigRequest 单个环境的部署配置变更，未指定的字段保持不变
+// map 中值为 null 表示删除该环境变量或绑定
+type PagesDeploymentConfigRequest struct {
+	CompatibilityDate  *string                        `json:"compatibilitydate" example:"2024-09-23"`
+	CompatibilityFlags *[]string                      `json:"compatibilityflags"`
+	EnvVars            map[string]*PagesEnvVarRequest `json:"envvars"`
+	KVNamespaces       map[string]*string             `json:"kvnamespaces"` // 绑定名 -> 命名空间 ID
+	R2Buckets          map[string]*string             `json:"r2buckets"`    // 绑定名 -> 桶名称
+	D1Databases        map[string]*string             `json:"d1databases"`  // 绑定名 -> 数据库 ID
+}
+
+// UpdatePagesProjectConfigRequest 更新 Pages 项目配置的请求结构
+type UpdatePagesProjectConfigRequest struct {
+	BuildCommand   *string                       `json:"buildcommand" example:"npm run build"`
+	DestinationDir *string                       `json:"destinationdir"`
+	RootDir        *string                       `json:"rootdir"`
+	Preview        *PagesDeploymentConfigRequest `json:"preview"`
+	Production     *PagesDeploymentConfigRequest `json:"production"`
+	Confirm        bool                          `json:"confirm"`     // 为 false 时只返回变更预览
+	PreviewHash    string                        `json:"previewhash"` // 确认执行时必填，取自预览结果的 hash
+}
+
+// toPatch 转换为服务层结构
+func (r *UpdatePagesProjectConfigRequest) toPatch() service.PagesConfigPatch {
+	return service.PagesConfigPatch{
+		BuildCommand:   r.BuildCommand,
+		DestinationDir: r.DestinationDir,
+		RootDir:        r.RootDir,
+		Preview:        r.Preview.toPatch(),
+		Production:     r.Production.toPatch(),
+	}
+}
+
+// toPatch 转换为服务层结构，未指定该环境时返回 nil
+func (r *PagesDeploymentConfigRequest) toPatch() *service.PagesDeploymentConfigPatch {
+	if r == nil {
+		return nil
+	}
+
+	var envVars map[string]*constants.EnvVarBinding
+	if r.EnvVars != nil {
+		envVars = make(map[string]*constants.EnvVarBinding, len(r.EnvVars))
+		for name, envVar := range r.EnvVars {
+			if envVar == nil {
+				envVars[name] = nil
+				continue
+			}
+			envVars[name] = &constants.EnvVarBinding{Type: envVar.Type, Value: envVar.Value}
+		}
+	}
+
+	return &service.PagesDeploymentConfigPatch{
+		CompatibilityDate:  r.CompatibilityDate,
+		CompatibilityFlags: r.CompatibilityFlags,
+		EnvVars:            envVars,
+		KVNamespaces:       r.KVNamespaces,
+		R2Buckets:          r.R2Buckets,
+		D1Databases:        r.D1Databases,
+	}
+}
+
+// UpdatePagesProjectConfigHandler godoc
+// @Summary      Update Pages project configuration
+// @Description  PATCH the build command and the preview/production deployment configs (env vars and secrets, KV, R2 and D1 bindings, compatibility date and flags). Without confirm the diff is returned; with confirm and the preview hash the changes are applied. Null map values remove an entry
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        request       body    UpdatePagesProjectConfigRequest  true  "Update Pages project configuration request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      409  {object}  model.Response  "Project configuration changed since preview"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname} [patch]
+func UpdatePagesProjectConfigHandler(c *gin.Context) {
+	var req UpdatePagesProjectConfigRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Confirm && req.PreviewHash == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "previewhash is required when confirm is true",
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	patch := req.toPatch()
+
+	if !req.Confirm {
+		plan, err := service.PreviewPagesConfigUpdate(headers.CountryCode, headers.Env, projectName, patch)
+		if err != nil {
+			handleCloudflareError(c, "preview Pages project configuration", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    200,
+			"message": "Success",
+			"data":    plan,
+		})
+		return
+	}
+
+	logger.Info("Updating configuration of Pages project %s for CountryCode: %s, Env: %s by %s",
+		projectName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	plan, err := service.ApplyPagesConfigUpdate(headers.CountryCode, headers.Env, projectName, patch, req.PreviewHash, c.GetString("username"))
+	if errors.Is(err, service.ErrPagesConfigChanged) {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Project configuration changed since preview, please review the new preview",
+			"error":   err.Error(),
+			"data":    plan,
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "update Pages project configuration", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    plan,
+	})
+}
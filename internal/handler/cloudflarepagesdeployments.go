@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListPagesDeploymentsRequest 获取部署列表的查询参数
+type ListPagesDeploymentsRequest struct {
+	Environment string `form:"environment" binding:"omitempty,oneof=production preview"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	PerPage     int    `form:"perpage" binding:"omitempty,min=1,max=25"`
+}
+
+// ListPagesDeploymentsHandler godoc
+// @Summary      List Pages deployments
+// @Description  List deployments of a Pages project with branch, commit hash, status and aliases, newest first
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true   "Pages project name"
+// @Param        environment   query   string  false  "production or preview"
+// @Param        page          query   int     false  "Page number"
+// @Param        perpage       query   int     false  "Items per page (max 25)"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/deployments [get]
+func ListPagesDeploymentsHandler(c *gin.Context) {
+	var req ListPagesDeploymentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	logger.Info("Listing deployments of Pages project %s for CountryCode: %s, Env: %s",
+		projectName, headers.CountryCode, headers.Env)
+
+	resp, err := service.ListPagesDeployments(headers.CountryCode, headers.Env, projectName, service.PagesDeploymentListOptions{
+		Environment: req.Environment,
+		Page:        req.Page,
+		PerPage:     req.PerPage,
+	})
+	if err != nil {
+		handleCloudflareError(c, "list Pages deployments", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    resp,
+	})
+}
+
+// GetPagesDeploymentHandler godoc
+// @Summary      Get Pages deployment
+// @Description  Get details and build stages of a Pages deployment
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        deploymentid  path    string  true  "Deployment ID"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid} [get]
+func GetPagesDeploymentHandler(c *gin.Context) {
+	runPagesDeploymentAction(c, "get Pages deployment", service.GetPagesDeployment)
+}
+
+// RollbackPagesDeploymentHandler godoc
+// @Summary      Roll back Pages production
+// @Description  Roll the production environment of a Pages project back to an earlier successful production deployment
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        deploymentid  path    string  true  "Deployment ID to roll back to"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/rollback [post]
+func RollbackPagesDeploymentHandler(c *gin.Context) {
+	runPagesDeploymentAction(c, "roll back Pages deployment", service.RollbackPagesDeployment)
+}
+
+// RetryPagesDeploymentHandler godoc
+// @Summary      Retry Pages deployment
+// @Description  Retry a failed Pages deployment
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        deploymentid  path    string  true  "Deployment ID"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/retry [post]
+func RetryPagesDeploymentHandler(c *gin.Context) {
+	runPagesDeploymentAction(c, "retry Pages deployment", service.RetryPagesDeployment)
+}
+
+// GetPagesDeploymentLogsHandler godoc
+// @Summary      Get Pages deployment build logs
+// @Description  Get the build logs of a Pages deployment. With stream=true or Accept: text/event-stream the logs are pushed as Server-Sent Events (log, status, done, error) until the deployment finishes
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true   "Pages project name"
+// @Param        deploymentid  path    string  true   "Deployment ID"
+// @Param        stream        query   bool    false  "Stream logs with Server-Sent Events"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/deployments/{deploymentid}/logs [get]
+func GetPagesDeploymentLogsHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	deploymentId := c.Param("deploymentid")
+	stream := c.Query("stream") == "true" || strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+
+	if !stream {
+		logs, err := service.GetPagesDeploymentLogs(headers.CountryCode, headers.Env, projectName, deploymentId)
+		if err != nil {
+			handleCloudflareError(c, "get Pages deployment logs", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    200,
+			"message": "Success",
+			"data":    logs,
+		})
+		return
+	}
+
+	logger.Info("Streaming logs of deployment %s of project %s for CountryCode: %s, Env: %s",
+		deploymentId, projectName, headers.CountryCode, headers.Env)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	err := service.StreamPagesDeploymentLogs(c.Request.Context(), headers.CountryCode, headers.Env, projectName, deploymentId,
+		func(event string, data interface{}) error {
+			c.SSEvent(event, data)
+			c.Writer.Flush()
+			return c.Request.Context().Err()
+		})
+	// 客户端断开时无需再推送
+	if err != nil && !errors.Is(err, c.Request.Context().Err()) {
+		logger.Error("Failed to stream deployment logs: %v", err)
+		c.SSEvent("error", gin.H{"error": err.Error()})
+		c.Writer.Flush()
+	}
+}
+
+// pagesDeploymentAction 对单个部署执行的服务层操作
+type pagesDeploymentAction func(countryCode, env, projectName, deploymentId string) (*constants.PagesDeployment, error)
+
+// runPagesDeploymentAction 从路径参数读取项目和部署 ID 并执行操作
+func runPagesDeploymentAction(c *gin.Context, operation string, action pagesDeploymentAction) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	deploymentId := c.Param("deploymentid")
+	logger.Info("Request to %s %s of project %s for CountryCode: %s, Env: %s by %s",
+		operation, deploymentId, projectName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	deployment, err := action(headers.CountryCode, headers.Env, projectName, deploymentId)
+	if err != nil {
+		handleCloudflareError(c, operation, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    deployment,
+	})
+}
@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"net/http"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddPagesDomainRequest 添加自定义域名的请求结构
+type AddPagesDomainRequest struct {
+	DomainName string `json:"domainname" binding:"required,fqdn" example:"id.example.com"`
+}
+
+// ListAllPagesDomainsHandler godoc
+// @Summary      List custom domains of all Pages projects
+// @Description  Custom domains and their status for every Pages project in the account
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/domains [get]
+func ListAllPagesDomainsHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Listing custom domains of all Pages projects for CountryCode: %s, Env: %s", headers.CountryCode, headers.Env)
+
+	domains, err := service.ListAllPagesDomains(headers.CountryCode, headers.Env)
+	if err != nil {
+		handleCloudflareError(c, "list Pages domains", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    domains,
+	})
+}
+
+// ListPagesDomainsHandler godoc
+// @Summary      List Pages project custom domains
+// @Description  List custom domains of a Pages project with verification and certificate validation status
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/domains [get]
+func ListPagesDomainsHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	domains, err := service.ListPagesDomains(headers.CountryCode, headers.Env, c.Param("projectname"))
+	if err != nil {
+		handleCloudflareError(c, "list Pages domains", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    domains,
+	})
+}
+
+// AddPagesDomainHandler godoc
+// @Summary      Add Pages project custom domain
+// @Description  Add a custom domain to a Pages project. The returned validation data describes the DNS records still required
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        request       body    AddPagesDomainRequest  true  "Add custom domain request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/domains [post]
+func AddPagesDomainHandler(c *gin.Context) {
+	var req AddPagesDomainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	logger.Info("Adding domain %s to Pages project %s for CountryCode: %s, Env: %s by %s",
+		req.DomainName, projectName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	domain, err := service.AddPagesDomain(headers.CountryCode, headers.Env, projectName, req.DomainName)
+	if err != nil {
+		handleCloudflareError(c, "add Pages domain", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    domain,
+	})
+}
+
+// GetPagesDomainHandler godoc
+// @Summary      Get Pages custom domain status
+// @Description  Get the verification and certificate validation status of a custom domain
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        domainname    path    string  true  "Domain name"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/domains/{domainname} [get]
+func GetPagesDomainHandler(c *gin.Context) {
+	runPagesDomainAction(c, "get Pages domain", service.GetPagesDomain)
+}
+
+// VerifyPagesDomainHandler godoc
+// @Summary      Retry Pages custom domain validation
+// @Description  Trigger a new verification of a custom domain that is pending or failed
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        domainname    path    string  true  "Domain name"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/domains/{domainname}/verify [post]
+func VerifyPagesDomainHandler(c *gin.Context) {
+	runPagesDomainAction(c, "verify Pages domain", service.RetryPagesDomainValidation)
+}
+
+// DeletePagesDomainHandler godoc
+// @Summary      Remove Pages custom domain
+// @Description  Remove a custom domain from a Pages project
+// @Tags         cloudflare-pages
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        projectname   path    string  true  "Pages project name"
+// @Param        domainname    path    string  true  "Domain name"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/pages/projects/{projectname}/domains/{domainname} [delete]
+func DeletePagesDomainHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	domainName := c.Param("domainname")
+	logger.Info("Removing domain %s from Pages project %s for CountryCode: %s, Env: %s by %s",
+		domainName, projectName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	if err := service.DeletePagesDomain(headers.CountryCode, headers.Env, projectName, domainName); err != nil {
+		handleCloudflareError(c, "delete Pages domain", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
+
+// runPagesDomainAction 从路径参数读取项目和域名并执行操作
+func runPagesDomainAction(c *gin.Context, operation string, action func(countryCode, env, projectName, domainName string) (*constants.PagesDomain, error)) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	projectName := c.Param("projectname")
+	domainName := c.Param("domainname")
+	logger.Info("Request to %s %s of project %s for CountryCode: %s, Env: %s",
+		operation, domainName, projectName, headers.CountryCode, headers.Env)
+
+	domain, err := action(headers.CountryCode, headers.Env, projectName, domainName)
+	if err != nil {
+		handleCloudflareError(c, operation, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    domain,
+	})
+}
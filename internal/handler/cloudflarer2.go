@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"openapi/internal/logger"
@@ -17,14 +18,21 @@ type GetBucketRequest struct {
 
 // DeleteDirectoryRequest 删除目录的请求结构
 type DeleteDirectoryRequest struct {
-	DirPath    string `json:"dirpath" binding:"required" example:"path/to/directory"`
-	BucketName string `json:"bucketname" binding:"required" example:"bucket-name"`
+	DirPath     string `json:"dirpath" binding:"required" example:"path/to/directory"`
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	Concurrency int    `json:"concurrency" binding:"omitempty,min=1,max=200" example:"50"` // 为空时使用配置的并发数
+	DryRun      bool   `json:"dryrun"`                                                     // 为 true 时只返回将删除的对象数量和总大小
+	Hard        bool   `json:"hard"`                                                       // 为 true 时永久删除，仅管理员可用；默认移入回收站
 }
 
 type CopyDirectoryRequest struct {
-	SourceDir  string `json:"sourcedir" binding:"required" example:"path/to/source/directory"`
-	TargetDir  string `json:"targetdir" binding:"required" example:"path/to/target/directory"`
-	BucketName string `json:"bucketname" binding:"required" example:"bucket-name"`
+	SourceDir   string `json:"sourcedir" binding:"required" example:"path/to/source/directory"`
+	TargetDir   string `json:"targetdir" binding:"required" example:"path/to/target/directory"`
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	Concurrency int    `json:"concurrency" binding:"omitempty,min=1,max=200" example:"50"` // 为空时使用配置的并发数
+	Sync        bool   `json:"sync"`                                                       // 为 true 时只复制目标中不存在或大小、ETag 不同的对象
+	DeleteExtra bool   `json:"deleteextra" binding:"excluded_without=Sync"`                // 增量同步时将目标目录中多余的对象移入回收站
+	Verify      bool   `json:"verify"`                                                     // 复制完成后校验目标目录与源目录一致，不一致时任务失败
 }
 
 // GetBucketHandler godoc
@@ -86,15 +94,15 @@ func GetBucketHandler(c *gin.Context) {
 
 // CopyDirectoryHandler godoc
 // @Summary      Copy directory
-// @Description  Copy a directory in Cloudflare R2 bucket
+// @Description  Start a background job that copies a directory in Cloudflare R2 bucket. Track it with GET /api/v1/jobs/{id}. With sync only objects missing at the target or differing in size or ETag are copied (counted in unchanged_objects otherwise); when either ETag comes from a multipart upload the contents are compared by MD5, and deleteextra also moves target objects that are not in the source to the trash of the target bucket (counted in deleted_objects; the trash entry is returned in trash_id and can be restored). deleteextra is refused when the target prefix is empty or the source prefix has no objects. With verify the job fails if the target does not match the source afterwards; mismatches are listed in errors
 // @Tags         cloudflare
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
 // @Param        request         body    CopyDirectoryRequest  true  "Copy directory request"
-// @Success      200  {object}  model.Response
-// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, overlapping directories, or deleteextra with an empty target directory or source"
 // @Failure      401  {object}  model.Response  "Unauthorized"
 // @Failure      500  {object}  model.Response  "Server error"
 // @Router       /api/v1/cloudflare/bucketinfo/copy [post]
@@ -122,8 +130,26 @@ func CopyDirectoryHandler(c *gin.Context) {
 	logger.Info("Copying directory in bucket %s from %s to %s",
 		req.BucketName, req.SourceDir, req.TargetDir)
 
-	// 调用服务层复制目录
-	err := service.CopyDirectory(headers.CountryCode, headers.Env, req.BucketName, req.SourceDir, req.TargetDir)
+	// 创建后台复制任务
+	job, err := service.CreateR2CopyJob(headers.CountryCode, headers.Env, req.BucketName, req.SourceDir, req.TargetDir, c.GetString("username"),
+		service.R2BulkOptions{Concurrency: req.Concurrency},
+		service.R2CopyOptions{Sync: req.Sync, DeleteExtra: req.DeleteExtra, Verify: req.Verify})
+	if errors.Is(err, service.ErrR2PrefixOverlap) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Source and target overlap",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrR2DeleteExtraUnsafe) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Cannot delete extra objects",
+			"error":   err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		logger.Error("Failed to copy directory: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -134,24 +160,28 @@ func CopyDirectoryHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "Successfully copied directory",
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Copy job created",
+		"data":    job,
 	})
 }
 
 // DeleteDirectoryHandler godoc
 // @Summary      Delete directory
-// @Description  Delete a directory in Cloudflare R2 bucket
+// @Description  Start a background job that moves a directory in Cloudflare R2 bucket to the trash (.trash/<time>/<dir>/); list it with GET /api/v1/cloudflare/r2/trash and undo with POST /api/v1/cloudflare/r2/trash/restore. With hard (admin only) the directory is deleted permanently with batched DeleteObjects calls (1000 keys each). Track the job with GET /api/v1/jobs/{id}. With dryrun the object count and total size are returned and nothing is deleted
 // @Tags         cloudflare
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
 // @Param        request         body    DeleteDirectoryRequest  true  "Delete directory request"
-// @Success      200  {object}  model.Response
-// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Success      200  {object}  model.Response  "Dry run result"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, or directory already in the trash"
 // @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      403  {object}  model.Response  "Hard delete requires admin"
+// @Failure      404  {object}  model.Response  "Directory is empty or does not exist"
 // @Failure      500  {object}  model.Response  "Server error"
 // @Router       /api/v1/cloudflare/bucketinfo [delete]
 func DeleteDirectoryHandler(c *gin.Context) {
@@ -175,10 +205,64 @@ func DeleteDirectoryHandler(c *gin.Context) {
 		return
 	}
 
-	logger.Info("Deleting directory %s in bucket %s", req.DirPath, req.BucketName)
+	if req.DryRun {
+		preview, err := service.PreviewDeleteDirectory(headers.CountryCode, headers.Env, req.BucketName, req.DirPath)
+		if err != nil {
+			handleCloudflareError(c, "preview directory deletion", err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"code":    200,
+			"message": "Success",
+			"data":    preview,
+		})
+		return
+	}
+
+	if !req.Hard {
+		logger.Info("Moving directory %s in bucket %s to trash by %s", req.DirPath, req.BucketName, c.GetString("username"))
+
+		// 创建后台移入回收站任务
+		job, err := service.TrashDirectory(headers.CountryCode, headers.Env, req.BucketName, req.DirPath, c.GetString("username"),
+			service.R2BulkOptions{Concurrency: req.Concurrency})
+		if errors.Is(err, service.ErrR2TrashPath) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "Directory is already in the trash, use purge or hard delete",
+				"error":   err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, service.ErrR2DirectoryEmpty) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    404,
+				"message": "Directory not found",
+				"error":   err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			handleCloudflareError(c, "move directory to trash", err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{
+			"code":    202,
+			"message": "Move to trash job created",
+			"data":    job,
+		})
+		return
+	}
+
+	if !requireAdmin(c) {
+		return
+	}
+
+	logger.Info("Deleting directory %s in bucket %s permanently by %s", req.DirPath, req.BucketName, c.GetString("username"))
 
-	// 调用服务层删除目录
-	err := service.DeleteDirectory(headers.CountryCode, headers.Env, req.BucketName, req.DirPath)
+	// 创建后台删除任务
+	job, err := service.CreateR2DeleteJob(headers.CountryCode, headers.Env, req.BucketName, req.DirPath, c.GetString("username"),
+		service.R2BulkOptions{Concurrency: req.Concurrency})
 	if err != nil {
 		logger.Error("Failed to delete directory: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -189,8 +273,9 @@ func DeleteDirectoryHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"code":    200,
-		"message": "Successfully deleted directory",
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Delete job created",
+		"data":    job,
 	})
 }
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"openapi/internal/logger"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// R2LocationRequest 表示某个环境下 R2 桶中的前缀
+type R2LocationRequest struct {
+	Env         string `json:"env" binding:"required" example:"pre"`
+	CountryCode string `json:"countrycode" binding:"required" example:"id"`
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	Prefix      string `json:"prefix" example:"v1.2.3/"` // 为空表示整个桶
+}
+
+// toLocation 转换为服务层结构
+func (r R2LocationRequest) toLocation() service.R2Location {
+	return service.R2Location{
+		Env:         r.Env,
+		CountryCode: r.CountryCode,
+		BucketName:  r.BucketName,
+		Prefix:      r.Prefix,
+	}
+}
+
+// CopyR2ObjectsRequest 跨桶复制的请求结构
+type CopyR2ObjectsRequest struct {
+	Source      R2LocationRequest `json:"source" binding:"required"`
+	Target      R2LocationRequest `json:"target" binding:"required"`
+	Concurrency int               `json:"concurrency" binding:"omitempty,min=1,max=200" example:"50"` // 为空时使用配置的并发数
+	Sync        bool              `json:"sync"`                                                       // 为 true 时只复制目标中不存在或大小、ETag 不同的对象
+	DeleteExtra bool              `json:"deleteextra" binding:"excluded_without=Sync"`                // 增量同步时将目标中多余的对象移入回收站，目标前缀不能为空
+	Verify      bool              `json:"verify"`                                                     // 复制完成后校验目标与源一致
+}
+
+// VerifyR2ObjectsRequest 校验两个前缀下对象一致的请求结构
+type VerifyR2ObjectsRequest struct {
+	Source R2LocationRequest `json:"source" binding:"required"`
+	Target R2LocationRequest `json:"target" binding:"required"`
+}
+
+// CopyR2ObjectsHandler godoc
+// @Summary      Copy R2 objects across buckets and accounts
+// @Description  Start a background job that copies every object under the source prefix to the target prefix, retrying transient failures. Track it with GET /api/v1/jobs/{id}; an interrupted job resumes after the last completed key. Source and target may be in different environments and Cloudflare accounts; the same account uses server-side copy, different accounts stream through this service. With sync only objects missing at the target or differing in size or ETag are copied (counted in unchanged_objects otherwise); when either ETag comes from a multipart upload the contents are compared by MD5, and deleteextra also moves target objects that are not in the source to the trash of the target bucket (counted in deleted_objects; the trash entry is returned in trash_id and can be restored). deleteextra is refused when the target prefix is empty or the source prefix has no objects. With verify the job fails if the target does not match the source afterwards; mismatches are listed in errors
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        request        body    CopyR2ObjectsRequest  true  "Copy R2 objects request"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body, overlapping prefixes, or deleteextra with an empty target prefix or source"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/copy [post]
+func CopyR2ObjectsHandler(c *gin.Context) {
+	var req CopyR2ObjectsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Copying R2 objects from %s/%s/%s to %s/%s/%s by %s (sync: %t, delete extra: %t)",
+		req.Source.Env, req.Source.BucketName, req.Source.Prefix,
+		req.Target.Env, req.Target.BucketName, req.Target.Prefix, c.GetString("username"), req.Sync, req.DeleteExtra)
+
+	// 创建后台复制任务
+	job, err := service.CreateR2TransferJob(req.Source.toLocation(), req.Target.toLocation(), c.GetString("username"),
+		service.R2BulkOptions{Concurrency: req.Concurrency},
+		service.R2CopyOptions{Sync: req.Sync, DeleteExtra: req.DeleteExtra, Verify: req.Verify})
+	if errors.Is(err, service.ErrR2PrefixOverlap) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Source and target overlap",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrR2DeleteExtraUnsafe) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Cannot delete extra objects",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "copy R2 objects", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Copy job created",
+		"data":    job,
+	})
+}
+
+// VerifyR2ObjectsHandler godoc
+// @Summary      Verify R2 objects
+// @Description  Compare the objects under the source and target prefixes: object counts, sizes and ETags. Multipart objects whose ETags differ are compared by the MD5 of their content. Returns match and the list of mismatches (missing, extra, size, etag or checksum), at most 1000
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        request        body    VerifyR2ObjectsRequest  true  "Verify R2 objects request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/verify [post]
+func VerifyR2ObjectsHandler(c *gin.Context) {
+	var req VerifyR2ObjectsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	logger.Info("Verifying R2 objects %s/%s/%s against %s/%s/%s by %s",
+		req.Target.Env, req.Target.BucketName, req.Target.Prefix,
+		req.Source.Env, req.Source.BucketName, req.Source.Prefix, c.GetString("username"))
+
+	result, err := service.VerifyR2Objects(req.Source.toLocation(), req.Target.toLocation())
+	if err != nil {
+		handleCloudflareError(c, "verify R2 objects", err)
+		return
+	}
+
+	message := "Success"
+	if !result.Match {
+		message = "Target does not match source"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": message,
+		"data":    result,
+	})
+}
@@ -0,0 +1,428 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListR2ObjectsRequest 浏览 R2 对象的查询参数
+type ListR2ObjectsRequest struct {
+	BucketName        string `form:"bucketname" binding:"required"`
+	Prefix            string `form:"prefix"`
+	Delimiter         string `form:"delimiter"`
+	ContinuationToken string `form:"continuationtoken"`
+	PageSize          int32  `form:"pagesize" binding:"omitempty,min=1,max=1000"`
+	Filter            string `form:"filter" binding:"omitempty,oneof=versions"`
+}
+
+// UploadR2ObjectRequest 上传单个对象的查询参数
+type UploadR2ObjectRequest struct {
+	BucketName  string `form:"bucketname" binding:"required"`
+	Key         string `form:"key" binding:"required"`
+	ContentType string `form:"contenttype"` // 为空时自动识别
+}
+
+// UploadR2ArchiveRequest 上传归档的查询参数
+type UploadR2ArchiveRequest struct {
+	BucketName string `form:"bucketname" binding:"required"`
+	Prefix     string `form:"prefix"`
+	Format     string `form:"format" binding:"omitempty,oneof=tar tgz zip"` // 为空时自动识别
+}
+
+// DownloadR2ObjectRequest 下载对象的查询参数
+type DownloadR2ObjectRequest struct {
+	BucketName string `form:"bucketname" binding:"required"`
+	Key        string `form:"key" binding:"required"`
+}
+
+// PresignR2ObjectRequest 生成预签名 URL 的请求结构
+type PresignR2ObjectRequest struct {
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	Key         string `json:"key" binding:"required" example:"v1.2.3/app.zip"`
+	Method      string `json:"method" binding:"omitempty,oneof=GET PUT" example:"GET"` // 默认 GET
+	Expires     int    `json:"expires" binding:"min=0" example:"900"`                  // 有效期（秒），0 表示使用默认值
+	ContentType string `json:"contenttype"`                                            // 仅 PUT，上传时必须携带相同的 Content-Type
+}
+
+// ListR2ObjectsHandler godoc
+// @Summary      Browse R2 objects
+// @Description  List folders and objects (size, ETag, last-modified, storage class) under a prefix with continuation-token paging. filter=versions keeps only version directories (latest or v*)
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers            header  middleware.RequestHeaders  true  "Request headers"
+// @Param        bucketname         query   string  true   "Bucket name"
+// @Param        prefix             query   string  false  "Key prefix, e.g. v1.2.3/"
+// @Param        delimiter          query   string  false  "Delimiter used to group folders, e.g. /"
+// @Param        continuationtoken  query   string  false  "Token from the previous page"
+// @Param        pagesize           query   int     false  "Page size (1-1000)"
+// @Param        filter             query   string  false  "versions"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/objects [get]
+func ListR2ObjectsHandler(c *gin.Context) {
+	var req ListR2ObjectsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	result, err := service.ListR2Objects(headers.CountryCode, headers.Env, req.BucketName, service.R2ListOptions{
+		Prefix:            req.Prefix,
+		Delimiter:         req.Delimiter,
+		ContinuationToken: req.ContinuationToken,
+		PageSize:          req.PageSize,
+		VersionsOnly:      req.Filter == "versions",
+	})
+	if err != nil {
+		handleCloudflareError(c, "list R2 objects", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    result,
+	})
+}
+
+// UploadR2ObjectHandler godoc
+// @Summary      Upload R2 object
+// @Description  Upload a single object from a multipart "file" field or the raw request body. Objects larger than 16MB use multipart upload. Content-Type is detected from the extension and content unless given
+// @Tags         cloudflare
+// @Accept       octet-stream,mpfd
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header    middleware.RequestHeaders  true  "Request headers"
+// @Param        bucketname    query     string  true   "Bucket name"
+// @Param        key           query     string  true   "Object key, e.g. v1.2.3/index.html"
+// @Param        contenttype   query     string  false  "Content-Type of the object"
+// @Param        file          formData  file    false  "Object content (or send it as the raw body)"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/objects/upload [post]
+func UploadR2ObjectHandler(c *gin.Context) {
+	var req UploadR2ObjectRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	reader, closeReader, ok := uploadBody(c)
+	if !ok {
+		return
+	}
+	defer closeReader()
+
+	logger.Info("Uploading R2 object %s to bucket %s for CountryCode: %s, Env: %s by %s",
+		req.Key, req.BucketName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	result, err := service.UploadR2Object(headers.CountryCode, headers.Env, req.BucketName, req.Key, req.ContentType, reader)
+	if err != nil {
+		handleCloudflareError(c, "upload R2 object", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    result,
+	})
+}
+
+// UploadR2ArchiveHandler godoc
+// @Summary      Upload and extract archive to R2
+// @Description  Extract a tar, tar.gz or zip archive from a multipart "file" field or the raw request body and upload every file under the prefix. The format is detected from the content unless given. The archive size, the total extracted size and the file count are limited by r2_archive_max_bytes (default 2GB) and r2_archive_max_entries (default 50000). If the upload fails part way, the files already uploaded are deleted; data reports uploaded and removed counts and lists any key that could not be removed in remaining
+// @Tags         cloudflare
+// @Accept       octet-stream,mpfd
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header    middleware.RequestHeaders  true  "Request headers"
+// @Param        bucketname    query     string  true   "Bucket name"
+// @Param        prefix        query     string  false  "Target prefix, e.g. v1.2.3/"
+// @Param        format        query     string  false  "tar, tgz or zip"
+// @Param        file          formData  file    false  "Archive (or send it as the raw body)"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      413  {object}  model.Response  "Archive too large"
+// @Failure      500  {object}  model.Response  "Server error, or upload failed and uploaded files were removed"
+// @Router       /api/v1/cloudflare/r2/objects/archive [post]
+func UploadR2ArchiveHandler(c *gin.Context) {
+	var req UploadR2ArchiveRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	reader, closeReader, ok := uploadBody(c)
+	if !ok {
+		return
+	}
+	defer closeReader()
+
+	logger.Info("Uploading archive to bucket %s under prefix %s for CountryCode: %s, Env: %s by %s",
+		req.BucketName, req.Prefix, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	result, err := service.UploadR2Archive(headers.CountryCode, headers.Env, req.BucketName, req.Prefix, req.Format, reader)
+	var archiveErr *service.R2ArchiveUploadError
+	errors.As(err, &archiveErr)
+	if errors.Is(err, service.ErrR2ArchiveTooLarge) {
+		logger.Error("Failed to upload R2 archive: %v", err)
+		response := gin.H{
+			"code":    413,
+			"message": "Archive too large",
+			"error":   err.Error(),
+		}
+		if archiveErr != nil {
+			response["data"] = archiveErr
+		}
+		c.JSON(http.StatusRequestEntityTooLarge, response)
+		return
+	}
+	if archiveErr != nil {
+		logger.Error("Failed to upload R2 archive: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to upload R2 archive, uploaded files were removed",
+			"error":   err.Error(),
+			"data":    archiveErr,
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "upload R2 archive", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    result,
+	})
+}
+
+// DownloadR2ObjectHandler godoc
+// @Summary      Download R2 object
+// @Description  Stream an object. A Range header (e.g. bytes=0-1023) returns 206 with Content-Range
+// @Tags         cloudflare
+// @Produce      octet-stream
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        Range         header  string  false  "Byte range, e.g. bytes=0-1023"
+// @Param        bucketname    query   string  true   "Bucket name"
+// @Param        key           query   string  true   "Object key"
+// @Success      200  {file}    file
+// @Success      206  {file}    file
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Object not found"
+// @Failure      416  {object}  model.Response  "Range not satisfiable"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/objects/download [get]
+func DownloadR2ObjectHandler(c *gin.Context) {
+	var req DownloadR2ObjectRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	object, err := service.GetR2Object(headers.CountryCode, headers.Env, req.BucketName, req.Key, c.GetHeader("Range"))
+	if errors.Is(err, service.ErrR2ObjectNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "Object not found",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrR2InvalidRange) {
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{
+			"code":    416,
+			"message": "Range not satisfiable",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "download R2 object", err)
+		return
+	}
+	defer object.Body.Close()
+
+	status := http.StatusOK
+	extraHeaders := map[string]string{
+		"Accept-Ranges": "bytes",
+		"ETag":          object.ETag,
+	}
+	if !object.LastModified.IsZero() {
+		extraHeaders["Last-Modified"] = object.LastModified.UTC().Format(http.TimeFormat)
+	}
+	if object.ContentRange != "" {
+		status = http.StatusPartialContent
+		extraHeaders["Content-Range"] = object.ContentRange
+	}
+
+	c.DataFromReader(status, object.ContentLength, object.ContentType, object.Body, extraHeaders)
+}
+
+// uploadBody 返回上传内容，multipart 请求读取 file 字段，否则读取原始请求体
+func uploadBody(c *gin.Context) (io.Reader, func(), bool) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		if c.Request.ContentLength == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"code":    400,
+				"message": "Request body is empty",
+			})
+			return nil, nil, false
+		}
+		return c.Request.Body, func() {}, true
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err == nil {
+		var file io.ReadCloser
+		if file, err = fileHeader.Open(); err == nil {
+			return file, func() { file.Close() }, true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"code":    400,
+		"message": "Invalid file",
+		"error":   err.Error(),
+	})
+	return nil, nil, false
+}
+
+// PresignR2ObjectHandler godoc
+// @Summary      Presign R2 object URL
+// @Description  Return a time-limited presigned GET or PUT URL for an object. The expiry defaults to r2_presign_default_expiry and may not exceed r2_presign_max_expiry. Returned headers must be sent with the request
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    PresignR2ObjectRequest  true  "Presign request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body, or expiry not allowed"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/presign [post]
+func PresignR2ObjectHandler(c *gin.Context) {
+	var req PresignR2ObjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if req.Method == "" {
+		req.Method = http.MethodGet
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Presigning %s %s in bucket %s for CountryCode: %s, Env: %s by %s",
+		req.Method, req.Key, req.BucketName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	presigned, err := service.PresignR2Object(headers.CountryCode, headers.Env, req.BucketName, req.Key,
+		req.Method, req.ContentType, time.Duration(req.Expires)*time.Second)
+	if errors.Is(err, service.ErrR2PresignExpiry) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Expiry not allowed",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		handleCloudflareError(c, "presign R2 object", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    presigned,
+	})
+}
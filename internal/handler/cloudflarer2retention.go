@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// R2RetentionPolicyRequest 保存版本目录保留策略的请求结构
+type R2RetentionPolicyRequest struct {
+	BucketName    string `json:"bucketname" binding:"required" example:"bucket-name"`
+	KeepCount     int    `json:"keep" binding:"required,min=1" example:"10"`           // 保留最新的版本目录数量
+	IntervalHours int    `json:"intervalhours" binding:"omitempty,min=1" example:"24"` // 定时执行间隔（小时），为空时不定时执行
+}
+
+// R2RetentionRequest 预览或执行版本目录保留策略的请求结构
+type R2RetentionRequest struct {
+	BucketName string `json:"bucketname" binding:"required" example:"bucket-name"`
+	KeepCount  int    `json:"keep" binding:"omitempty,min=1" example:"10"` // 为空时使用 bucket 已配置的策略
+}
+
+// ListR2RetentionPoliciesHandler godoc
+// @Summary      List R2 retention policies
+// @Description  List the version directory retention policies of all buckets in the environment
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/retention [get]
+func ListR2RetentionPoliciesHandler(c *gin.Context) {
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	policies, err := service.ListR2RetentionPolicies(headers.CountryCode, headers.Env)
+	if err != nil {
+		handleCloudflareError(c, "list retention policies", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    policies,
+	})
+}
+
+// SaveR2RetentionPolicyHandler godoc
+// @Summary      Save R2 retention policy
+// @Description  Create or update the retention policy of a bucket: keep the newest N vX.Y.Z directories plus latest and every version referenced by a KV key matching kv_key_patterns. With intervalhours the policy is executed on that schedule
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    R2RetentionPolicyRequest   true  "Retention policy"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/retention [put]
+func SaveR2RetentionPolicyHandler(c *gin.Context) {
+	var req R2RetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	policy, err := service.SaveR2RetentionPolicy(headers.CountryCode, headers.Env, req.BucketName,
+		req.KeepCount, req.IntervalHours, c.GetString("username"))
+	if err != nil {
+		handleCloudflareError(c, "save retention policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    policy,
+	})
+}
+
+// DeleteR2RetentionPolicyHandler godoc
+// @Summary      Delete R2 retention policy
+// @Description  Delete the retention policy of a bucket. Delete jobs already created are not affected
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    GetBucketRequest           true  "Bucket"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Retention policy not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/retention [delete]
+func DeleteR2RetentionPolicyHandler(c *gin.Context) {
+	var req GetBucketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	if err := service.DeleteR2RetentionPolicy(headers.CountryCode, headers.Env, req.BucketName, c.GetString("username")); err != nil {
+		handleR2RetentionError(c, "delete retention policy", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+	})
+}
+
+// PreviewR2RetentionHandler godoc
+// @Summary      Preview R2 retention
+// @Description  List the version directories the retention policy would keep (with the reason) and delete (with object count and size). Nothing is deleted. keep overrides the configured count
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    R2RetentionRequest         true  "Retention request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Retention policy not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/retention/preview [post]
+func PreviewR2RetentionHandler(c *gin.Context) {
+	var req R2RetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	plan, err := service.PreviewR2Retention(headers.CountryCode, headers.Env, req.BucketName, req.KeepCount)
+	if err != nil {
+		handleR2RetentionError(c, "preview retention", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    plan,
+	})
+}
+
+// ExecuteR2RetentionHandler godoc
+// @Summary      Execute R2 retention
+// @Description  Start a background job moving every version directory the retention policy does not keep to the trash, where it can be restored until the trash is purged. Track them with GET /api/v1/jobs/{id}. keep overrides the configured count
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    R2RetentionRequest         true  "Retention request"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Retention policy not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/retention/execute [post]
+func ExecuteR2RetentionHandler(c *gin.Context) {
+	var req R2RetentionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Executing retention of bucket %s by %s", req.BucketName, c.GetString("username"))
+
+	result, err := service.ExecuteR2Retention(headers.CountryCode, headers.Env, req.BucketName, req.KeepCount, c.GetString("username"))
+	if err != nil {
+		handleR2RetentionError(c, "execute retention", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Move to trash jobs created",
+		"data":    result,
+	})
+}
+
+// handleR2RetentionError 处理保留策略错误，策略不存在返回 404
+func handleR2RetentionError(c *gin.Context, operation string, err error) {
+	if errors.Is(err, service.ErrR2RetentionPolicyNotFound) {
+		logger.Error("Failed to %s: %v", operation, err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "Retention policy not found",
+			"error":   err.Error(),
+		})
+		return
+	}
+	handleCloudflareError(c, operation, err)
+}
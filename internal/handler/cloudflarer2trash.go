@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListR2TrashRequest 列出回收站的查询参数
+type ListR2TrashRequest struct {
+	BucketName string `form:"bucketname" binding:"required"`
+}
+
+// RestoreR2TrashRequest 从回收站恢复目录的请求结构
+type RestoreR2TrashRequest struct {
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	ID          string `json:"id" binding:"required" example:"20240101T120000Z-1a2b3c4d/v1.2.3"` // 回收站条目 ID
+	Concurrency int    `json:"concurrency" binding:"omitempty,min=1,max=200" example:"50"`       // 为空时使用配置的并发数
+}
+
+// ListR2TrashHandler godoc
+// @Summary      List R2 trash
+// @Description  List directories moved to the trash of a bucket, newest first, with the time they were deleted and when they will be purged
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers     header  middleware.RequestHeaders  true  "Request headers"
+// @Param        bucketname  query   string                     true  "Bucket name"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or query"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/trash [get]
+func ListR2TrashHandler(c *gin.Context) {
+	var req ListR2TrashRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Error("Invalid request query: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request query",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	entries, err := service.ListR2Trash(headers.CountryCode, headers.Env, req.BucketName)
+	if err != nil {
+		handleCloudflareError(c, "list trash", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    entries,
+	})
+}
+
+// RestoreR2TrashHandler godoc
+// @Summary      Restore R2 directory from trash
+// @Description  Start a background job that moves a trash entry back to its original directory. Refused if the directory has objects again or the entry is still being processed by another job. Track it with GET /api/v1/jobs/{id}
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    RestoreR2TrashRequest      true  "Restore request"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Trash entry not found"
+// @Failure      409  {object}  model.Response  "Directory not empty or entry busy"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/trash/restore [post]
+func RestoreR2TrashHandler(c *gin.Context) {
+	var req RestoreR2TrashRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	job, err := service.RestoreR2Trash(headers.CountryCode, headers.Env, req.BucketName, req.ID, c.GetString("username"),
+		service.R2BulkOptions{Concurrency: req.Concurrency})
+	if err != nil {
+		logger.Error("Failed to restore trash entry: %v", err)
+		switch {
+		case errors.Is(err, service.ErrR2TrashEntryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{
+				"code":    404,
+				"message": "Trash entry not found",
+				"error":   err.Error(),
+			})
+		case errors.Is(err, service.ErrR2RestoreConflict):
+			c.JSON(http.StatusConflict, gin.H{
+				"code":    409,
+				"message": "Cannot restore trash entry",
+				"error":   err.Error(),
+			})
+		default:
+			handleCloudflareError(c, "restore trash entry", err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Restore job created",
+		"data":    job,
+	})
+}
+
+// PurgeR2TrashHandler godoc
+// @Summary      Purge R2 trash
+// @Description  Start background delete jobs for trash entries older than r2_trash_retention_hours (default 168). The same purge runs every hour for every bucket that has used the trash
+// @Tags         cloudflare
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers  header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request  body    GetBucketRequest           true  "Bucket"
+// @Success      202  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/r2/trash/purge [post]
+func PurgeR2TrashHandler(c *gin.Context) {
+	var req GetBucketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	jobs, err := service.PurgeR2Trash(headers.CountryCode, headers.Env, req.BucketName, c.GetString("username"))
+	if err != nil {
+		handleCloudflareError(c, "purge trash", err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"code":    202,
+		"message": "Purge jobs created",
+		"data":    jobs,
+	})
+}
@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"openapi/internal/logger"
+	"openapi/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +30,30 @@ func validateCloudflareRequest(c *gin.Context) (CountryCode, Env, NamespaceId, K
 	return
 }
 
+// requireAdmin 检查当前用户是否为管理员，不是时返回 403
+func requireAdmin(c *gin.Context) bool {
+	isAdmin, err := service.IsAdmin(c.GetUint("user_id"))
+	if err != nil {
+		logger.Error("Failed to check admin for user %s: %v", c.GetString("username"), err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to check user",
+			"error":   err.Error(),
+		})
+		return false
+	}
+	if !isAdmin {
+		logger.Error("User %s is not an admin", c.GetString("username"))
+		c.JSON(http.StatusForbidden, gin.H{
+			"code":    403,
+			"message": "Admin only",
+			"error":   service.ErrNotAdmin.Error(),
+		})
+		return false
+	}
+	return true
+}
+
 // handleCloudflareError 处理 Cloudflare 错误响应
 func handleCloudflareError(c *gin.Context, operation string, err error) {
 	logger.Error("Failed to %s: %v", operation, err)
@@ -37,3 +63,32 @@ func handleCloudflareError(c *gin.Context, operation string, err error) {
 		"error":   err.Error(),
 	})
 }
+
+// handleKVWriteError 处理 KV 写入错误，值校验失败返回 400 及详细校验信息
+func handleKVWriteError(c *gin.Context, operation string, err error) {
+	var validationErr *service.KVValidationError
+	if errors.As(err, &validationErr) {
+		logger.Error("Failed to %s: %v", operation, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Value validation failed",
+			"error":   err.Error(),
+			"data":    []*service.KVValidationError{validationErr},
+		})
+		return
+	}
+
+	var validationErrs service.KVValidationErrors
+	if errors.As(err, &validationErrs) {
+		logger.Error("Failed to %s: %v", operation, err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Value validation failed",
+			"error":   err.Error(),
+			"data":    validationErrs,
+		})
+		return
+	}
+
+	handleCloudflareError(c, operation, err)
+}
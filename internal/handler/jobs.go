@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"openapi/internal/logger"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListJobsHandler godoc
+// @Summary      List background jobs
+// @Description  List background jobs, newest first
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer {token}"
+// @Param        env            query   string  false  "Environment"
+// @Param        type           query   string  false  "r2_copy, r2_delete, r2_move or release"
+// @Param        status         query   string  false  "pending, running, succeeded, failed or cancelled"
+// @Param        limit          query   int     false  "Max jobs (default 50)"
+// @Success      200  {object}  model.Response
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/jobs [get]
+func ListJobsHandler(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	jobs, err := service.ListJobs(c.Query("env"), c.Query("type"), c.Query("status"), limit)
+	if err != nil {
+		logger.Error("Failed to list jobs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to list jobs",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    jobs,
+	})
+}
+
+// GetJobHandler godoc
+// @Summary      Get background job
+// @Description  Get the status and progress (objects and bytes done/total, failed objects and their errors) of a background job
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        id             path    int     true  "Job ID"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid job ID"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Job not found"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/jobs/{id} [get]
+func GetJobHandler(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	job, err := service.GetJob(id)
+	if err != nil {
+		handleJobError(c, "get job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    job,
+	})
+}
+
+// CancelJobHandler godoc
+// @Summary      Cancel background job
+// @Description  Cancel a pending or running job. Objects already processed are not rolled back
+// @Tags         jobs
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer {token}"
+// @Param        id             path    int     true  "Job ID"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid job ID"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      404  {object}  model.Response  "Job not found"
+// @Failure      409  {object}  model.Response  "Job already finished"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/jobs/{id}/cancel [post]
+func CancelJobHandler(c *gin.Context) {
+	id, ok := parseJobID(c)
+	if !ok {
+		return
+	}
+
+	logger.Info("Cancelling job %d by %s", id, c.GetString("username"))
+
+	job, err := service.CancelJob(id, c.GetString("username"))
+	if errors.Is(err, service.ErrJobFinished) {
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Job already finished",
+			"error":   err.Error(),
+			"data":    job,
+		})
+		return
+	}
+	if err != nil {
+		handleJobError(c, "cancel job", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    job,
+	})
+}
+
+// parseJobID 解析路径中的任务 ID
+func parseJobID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid job ID",
+			"error":   err.Error(),
+		})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// handleJobError 处理任务查询错误，任务不存在返回 404
+func handleJobError(c *gin.Context, operation string, err error) {
+	logger.Error("Failed to %s: %v", operation, err)
+	if errors.Is(err, service.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code":    404,
+			"message": "Job not found",
+			"error":   err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"code":    500,
+		"message": "Failed to " + operation,
+		"error":   err.Error(),
+	})
+}
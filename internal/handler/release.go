@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"openapi/internal/logger"
+	"openapi/internal/middleware"
+	"openapi/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReleaseRequest 发布静态站点的请求结构
+type ReleaseRequest struct {
+	BucketName  string `json:"bucketname" binding:"required" example:"bucket-name"`
+	SourceDir   string `json:"sourcedir" example:"latest"` // 为空时不复制，要求版本目录已存在
+	Version     string `json:"version" binding:"required" example:"v1.2.3"`
+	NameSpaceId string `json:"namespaceid" binding:"required"`
+	KeyName     string `json:"keyname" binding:"required" example:"ProdVersion"`
+}
+
+// RollbackReleaseRequest 回滚发布的请求结构
+type RollbackReleaseRequest struct {
+	ID uint `json:"id" binding:"required" example:"1"`
+}
+
+// ReleaseHandler godoc
+// @Summary      Release static site
+// @Description  Asynchronous with sourcedir: a background job copies the build directory into the R2 version directory, verifies that every object matches the build directory (count, size and ETag) and that it is non-empty, then points the KV version key at it. 202 is returned as soon as the job is created, with release_id, job_id and the release in its initial copying status; track the final status with GET /api/v1/jobs/{id} or GET /api/v1/cloudflare/release. Synchronous without sourcedir: the existing version directory is checked, the key is switched immediately and 200 is returned with the succeeded release. The previous value is recorded for rollback. Only one release of a key can run at a time
+// @Tags         cloudflare-release
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    ReleaseRequest  true  "Release request"
+// @Success      200  {object}  model.Response  "Released (without sourcedir)"
+// @Success      202  {object}  model.Response  "Release job created (with sourcedir), data holds release_id, job_id and release"
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      409  {object}  model.Response  "Another release of the key is in progress"
+// @Failure      500  {object}  model.Response  "Release failed, KV unchanged"
+// @Router       /api/v1/cloudflare/release [post]
+func ReleaseHandler(c *gin.Context) {
+	var req ReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Releasing %s to %s for CountryCode: %s, Env: %s by %s",
+		req.Version, req.KeyName, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	record, err := service.Release(headers.CountryCode, headers.Env, service.ReleaseOptions{
+		BucketName:  req.BucketName,
+		SourceDir:   req.SourceDir,
+		Version:     req.Version,
+		NamespaceID: req.NameSpaceId,
+		KeyName:     req.KeyName,
+		Operator:    c.GetString("username"),
+	})
+	if errors.Is(err, service.ErrReleaseInProgress) {
+		logger.Error("Failed to release: %v", err)
+		c.JSON(http.StatusConflict, gin.H{
+			"code":    409,
+			"message": "Another release is in progress",
+			"error":   err.Error(),
+		})
+		return
+	}
+	if err != nil && record != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Release failed",
+			"error":   err.Error(),
+			"data":    record,
+		})
+		return
+	}
+	if err != nil {
+		handleKVWriteError(c, "release", err)
+		return
+	}
+	// 指定构建目录时发布由后台任务完成，此时只返回任务和发布记录的 ID，最终状态需另行查询
+	if record.JobID != 0 {
+		c.JSON(http.StatusAccepted, gin.H{
+			"code":    202,
+			"message": "Release job created",
+			"data": gin.H{
+				"release_id": record.ID,
+				"job_id":     record.JobID,
+				"release":    record,
+			},
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    record,
+	})
+}
+
+// RollbackReleaseHandler godoc
+// @Summary      Roll back release
+// @Description  Restore the KV version key to the value recorded before a release. Refused if the key no longer points at that release
+// @Tags         cloudflare-release
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        request       body    RollbackReleaseRequest  true  "Rollback release request"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers or body"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/release/rollback [post]
+func RollbackReleaseHandler(c *gin.Context) {
+	var req RollbackReleaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"code":    400,
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+		return
+	}
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	logger.Info("Rolling back release %d for CountryCode: %s, Env: %s by %s",
+		req.ID, headers.CountryCode, headers.Env, c.GetString("username"))
+
+	record, err := service.RollbackRelease(headers.CountryCode, headers.Env, req.ID, c.GetString("username"))
+	if err != nil {
+		handleKVWriteError(c, "roll back release", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    record,
+	})
+}
+
+// ListReleasesHandler godoc
+// @Summary      List releases
+// @Description  List release records of the environment, newest first
+// @Tags         cloudflare-release
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        headers         header  middleware.RequestHeaders  true  "Request headers"
+// @Param        namespaceid   query   string  false  "Namespace ID"
+// @Param        keyname       query   string  false  "KV version key"
+// @Param        limit         query   int     false  "Max records (default 50)"
+// @Success      200  {object}  model.Response
+// @Failure      400  {object}  model.Response  "Invalid request headers"
+// @Failure      401  {object}  model.Response  "Unauthorized"
+// @Failure      500  {object}  model.Response  "Server error"
+// @Router       /api/v1/cloudflare/release [get]
+func ListReleasesHandler(c *gin.Context) {
+	// 从上下文中获取已验证的请求头信息
+	headers := middleware.GetHeadersFromContext(c)
+	if headers == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to get headers from context",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	records, err := service.ListReleases(headers.Env, c.Query("namespaceid"), c.Query("keyname"), limit)
+	if err != nil {
+		logger.Error("Failed to list releases: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"code":    500,
+			"message": "Failed to list releases",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"code":    200,
+		"message": "Success",
+		"data":    records,
+	})
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 后台任务类型
+const (
+	JobTypeR2Copy   = "r2_copy"   // 复制 R2 目录
+	JobTypeR2Delete = "r2_delete" // 删除 R2 目录
+	JobTypeR2Move   = "r2_move"   // 移动 R2 目录（复制后删除源对象），用于移入回收站和从回收站恢复
+	JobTypeRelease  = "release"   // 发布静态站点：复制并校验构建目录后切换 KV 版本
+)
+
+// 后台任务状态
+const (
+	JobStatusPending   = "pending"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// JobMaxErrors 任务记录中保留的最大错误条数
+const JobMaxErrors = 100
+
+// JobError 单个对象的处理错误
+type JobError struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// Job 后台任务表
+type Job struct {
+	gorm.Model
+	Type             string     `gorm:"column:type;type:varchar(20);not null;index" json:"type"`
+	Environment      string     `gorm:"column:environment;type:varchar(20);not null" json:"environment"`
+	CountryCode      string     `gorm:"column:country_code;type:varchar(20);not null" json:"country_code"`
+	BucketName       string     `gorm:"column:bucket_name;type:varchar(100);not null" json:"bucket_name"`
+	SourceDir        string     `gorm:"column:source_dir;type:varchar(255);not null" json:"source_dir"` // 删除任务中为要删除的目录
+	TargetDir        string     `gorm:"column:target_dir;type:varchar(255)" json:"target_dir,omitempty"`
+	TargetEnv        string     `gorm:"column:target_environment;type:varchar(20)" json:"target_environment,omitempty"`   // 复制到其他环境时的目标环境，为空时与源相同
+	TargetCountry    string     `gorm:"column:target_country_code;type:varchar(20)" json:"target_country_code,omitempty"` // 与 TargetEnv 一起设置
+	TargetBucket     string     `gorm:"column:target_bucket_name;type:varchar(100)" json:"target_bucket_name,omitempty"`  // 复制到其他桶时的目标桶，为空时与源相同
+	Status           string     `gorm:"column:status;type:varchar(20);not null;index" json:"status"`
+	TotalObjects     int64      `gorm:"column:total_objects" json:"total_objects"`
+	TotalBytes       int64      `gorm:"column:total_bytes" json:"total_bytes"`
+	DoneObjects      int64      `gorm:"column:done_objects" json:"done_objects"`
+	DoneBytes        int64      `gorm:"column:done_bytes" json:"done_bytes"`
+	FailedObjects    int64      `gorm:"column:failed_objects" json:"failed_objects"`
+	SkippedObjects   int64      `gorm:"column:skipped_objects" json:"skipped_objects"`                // 源对象已不存在等原因未处理的对象
+	Sync             bool       `gorm:"column:sync" json:"sync"`                                      // 复制任务只复制目标中不存在或已变化的对象
+	DeleteExtra      bool       `gorm:"column:delete_extra" json:"delete_extra"`                      // 增量同步完成后将目标中多余的对象移入回收站
+	UnchangedObjects int64      `gorm:"column:unchanged_objects" json:"unchanged_objects"`            // 增量同步中未变化而跳过的对象，计入 DoneObjects
+	DeletedObjects   int64      `gorm:"column:deleted_objects" json:"deleted_objects"`                // 增量同步中移入回收站的目标多余对象
+	TrashID          string     `gorm:"column:trash_id;type:varchar(255)" json:"trash_id,omitempty"`  // 多余对象所在的回收站条目，可以通过回收站恢复
+	Verify           bool       `gorm:"column:verify" json:"verify"`                                  // 复制完成后校验目标目录与源目录一致
+	ReleaseID        uint       `gorm:"column:release_id" json:"release_id,omitempty"`                // 发布任务对应的发布记录
+	Concurrency      int        `gorm:"column:concurrency" json:"concurrency"`                        // 0 表示使用配置的并发数
+	LastKey          string     `gorm:"column:last_key;type:varchar(1024)" json:"last_key,omitempty"` // 该 key 及之前的对象均已处理，重启后从其后继续
+	Errors           []JobError `gorm:"column:errors;type:longtext;serializer:json" json:"errors"`    // 最多保留 JobMaxErrors 条
+	Error            string     `gorm:"column:error;type:text" json:"error,omitempty"`                // 导致任务失败的错误
+	Operator         string     `gorm:"column:operator;type:varchar(50)" json:"operator"`
+	StartedAt        *time.Time `gorm:"column:started_at" json:"started_at,omitempty"`
+	FinishedAt       *time.Time `gorm:"column:finished_at" json:"finished_at,omitempty"`
+}
+
+// TableName 指定表名
+func (Job) TableName() string {
+	return "job_record"
+}
+
+// IsFinished 任务是否已结束
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
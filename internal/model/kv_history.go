@@ -0,0 +1,34 @@
+package model
+
+import "gorm.io/gorm"
+
+// KV 变更操作类型
+const (
+	KVOperationUpdate  = "update"
+	KVOperationDelete  = "delete"
+	KVOperationRestore = "restore"
+)
+
+// KVValueHistory KV 值变更历史表
+type KVValueHistory struct {
+	gorm.Model
+	Environment   string `gorm:"column:environment;type:varchar(20);not null" json:"environment"`
+	CountryCode   string `gorm:"column:country_code;type:varchar(20);not null" json:"country_code"`
+	NamespaceID   string `gorm:"column:namespace_id;type:varchar(64);not null;index:idx_kv_history_key" json:"namespace_id"`
+	KeyName       string `gorm:"column:key_name;type:varchar(512);not null;index:idx_kv_history_key" json:"key_name"`
+	Operation     string `gorm:"column:operation;type:varchar(20);not null" json:"operation"`
+	OldExists     bool   `gorm:"column:old_exists" json:"old_exists"` // 写入前 key 是否存在
+	OldValue      string `gorm:"column:old_value;type:longtext" json:"old_value"`
+	OldMetadata   string `gorm:"column:old_metadata;type:text" json:"old_metadata,omitempty"` // 写入前的元数据（JSON）
+	OldExpiration int64  `gorm:"column:old_expiration" json:"old_expiration,omitempty"`       // 写入前的过期时间（Unix 秒），0 表示不过期
+	NewExists     bool   `gorm:"column:new_exists" json:"new_exists"`                         // 写入后 key 是否存在，删除时为 false
+	NewValue      string `gorm:"column:new_value;type:longtext" json:"new_value"`
+	NewMetadata   string `gorm:"column:new_metadata;type:text" json:"new_metadata,omitempty"`
+	NewExpiration int64  `gorm:"column:new_expiration" json:"new_expiration,omitempty"`
+	Operator      string `gorm:"column:operator;type:varchar(50)" json:"operator"`
+}
+
+// TableName 指定表名
+func (KVValueHistory) TableName() string {
+	return "kv_value_history"
+}
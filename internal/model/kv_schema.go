@@ -0,0 +1,28 @@
+package model
+
+import "gorm.io/gorm"
+
+// KV 值校验规则类型
+const (
+	KVRuleTypeJSONSchema = "json_schema"
+	KVRuleTypeRegex      = "regex"
+
+	// KVSchemaAnyNamespace 匹配所有命名空间
+	KVSchemaAnyNamespace = "*"
+)
+
+// KVValueSchema KV 值校验规则表
+type KVValueSchema struct {
+	gorm.Model
+	NamespaceID string `gorm:"column:namespace_id;type:varchar(64);not null;index" json:"namespace_id"` // * 表示所有命名空间
+	KeyPattern  string `gorm:"column:key_pattern;type:varchar(255);not null" json:"key_pattern"`        // key 通配符，* 匹配任意字符（包括 /），如 *ProdVersion*
+	RuleType    string `gorm:"column:rule_type;type:varchar(20);not null" json:"rule_type"`             // json_schema 或 regex
+	Rule        string `gorm:"column:rule;type:longtext;not null" json:"rule"`
+	Description string `gorm:"column:description;type:varchar(255)" json:"description"`
+	IsActive    bool   `gorm:"column:is_active;default:true" json:"is_active"`
+}
+
+// TableName 指定表名
+func (KVValueSchema) TableName() string {
+	return "kv_value_schema"
+}
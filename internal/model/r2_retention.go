@@ -0,0 +1,25 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// R2RetentionPolicy R2 版本目录保留策略表，每个环境、国家和 bucket 一条
+type R2RetentionPolicy struct {
+	gorm.Model
+	Environment   string     `gorm:"column:environment;type:varchar(20);not null;uniqueIndex:idx_retention_bucket" json:"environment"`
+	CountryCode   string     `gorm:"column:country_code;type:varchar(20);not null;uniqueIndex:idx_retention_bucket" json:"country_code"`
+	BucketName    string     `gorm:"column:bucket_name;type:varchar(100);not null;uniqueIndex:idx_retention_bucket" json:"bucket_name"`
+	KeepCount     int        `gorm:"column:keep_count;not null" json:"keep_count"`            // 保留最新的版本目录数量
+	IntervalHours int        `gorm:"column:interval_hours" json:"interval_hours"`             // 定时执行间隔（小时），0 表示不定时执行
+	LastRunAt     *time.Time `gorm:"column:last_run_at" json:"last_run_at,omitempty"`         // 最近一次执行时间
+	LastError     string     `gorm:"column:last_error;type:text" json:"last_error,omitempty"` // 最近一次执行的错误
+	Operator      string     `gorm:"column:operator;type:varchar(50)" json:"operator"`
+}
+
+// TableName 指定表名
+func (R2RetentionPolicy) TableName() string {
+	return "r2_retention_policy"
+}
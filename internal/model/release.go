@@ -0,0 +1,37 @@
+package model
+
+import "gorm.io/gorm"
+
+// 发布状态
+const (
+	ReleaseStatusCopying    = "copying"     // 正在复制构建目录到版本目录
+	ReleaseStatusVerifying  = "verifying"   // 正在检查版本目录
+	ReleaseStatusSwitching  = "switching"   // 正在切换 KV 版本
+	ReleaseStatusSucceeded  = "succeeded"   // 发布成功
+	ReleaseStatusFailed     = "failed"      // 发布失败，KV 未切换
+	ReleaseStatusRolledBack = "rolled_back" // 已回滚到上一版本
+)
+
+// ReleaseRecord 静态站点发布记录表
+type ReleaseRecord struct {
+	gorm.Model
+	Environment     string  `gorm:"column:environment;type:varchar(20);not null" json:"environment"`
+	CountryCode     string  `gorm:"column:country_code;type:varchar(20);not null" json:"country_code"`
+	BucketName      string  `gorm:"column:bucket_name;type:varchar(100);not null" json:"bucket_name"`
+	SourceDir       string  `gorm:"column:source_dir;type:varchar(255)" json:"source_dir"` // 为空表示版本目录已存在，不复制
+	Version         string  `gorm:"column:version;type:varchar(100);not null" json:"version"`
+	NamespaceID     string  `gorm:"column:namespace_id;type:varchar(64);not null;index:idx_release_key" json:"namespace_id"`
+	KeyName         string  `gorm:"column:key_name;type:varchar(512);not null;index:idx_release_key" json:"key_name"`
+	PreviousVersion string  `gorm:"column:previous_version;type:longtext" json:"previous_version"` // 发布前的 KV 值，用于回滚
+	PreviousExists  bool    `gorm:"column:previous_exists" json:"previous_exists"`
+	Status          string  `gorm:"column:status;type:varchar(20);not null" json:"status"`
+	Error           string  `gorm:"column:error;type:text" json:"error,omitempty"`
+	Operator        string  `gorm:"column:operator;type:varchar(50)" json:"operator"`
+	JobID           uint    `gorm:"column:job_id" json:"job_id,omitempty"`                   // 复制构建目录的后台任务，不复制时为 0
+	ActiveKey       *string `gorm:"column:active_key;type:varchar(64);uniqueIndex" json:"-"` // 发布未结束时为环境、命名空间和 key 的摘要，结束后置空，保证同一 key 同时只有一个发布
+}
+
+// TableName 指定表名
+func (ReleaseRecord) TableName() string {
+	return "release_record"
+}
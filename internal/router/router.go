@@ -53,6 +53,14 @@ func SetupRouter() *gin.Engine {
 				services.POST("/publish", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.PublicServiceHandler)
 			}
 
+			// 后台任务路由组
+			jobs := protected.Group("/jobs")
+			{
+				jobs.GET("", handler.ListJobsHandler)
+				jobs.GET("/:id", handler.GetJobHandler)
+				jobs.POST("/:id/cancel", handler.CancelJobHandler)
+			}
+
 			// 系统管理路由组
 			system := protected.Group("/system")
 			{
@@ -64,13 +72,64 @@ func SetupRouter() *gin.Engine {
 			{
 				cloudflare.GET("/pages/info", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetPagesProjectHandlerInfo)
 				cloudflare.GET("/pages/projects", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetPagesProjectHandler)
+				cloudflare.PATCH("/pages/projects/:projectname", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.UpdatePagesProjectConfigHandler)
+				cloudflare.GET("/pages/projects/:projectname/deployments", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListPagesDeploymentsHandler)
+				cloudflare.GET("/pages/projects/:projectname/deployments/:deploymentid", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetPagesDeploymentHandler)
+				cloudflare.GET("/pages/projects/:projectname/deployments/:deploymentid/logs", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetPagesDeploymentLogsHandler)
+				cloudflare.POST("/pages/projects/:projectname/deployments/:deploymentid/rollback", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RollbackPagesDeploymentHandler)
+				cloudflare.POST("/pages/projects/:projectname/deployments/:deploymentid/retry", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RetryPagesDeploymentHandler)
+				cloudflare.GET("/pages/domains", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListAllPagesDomainsHandler)
+				cloudflare.GET("/pages/projects/:projectname/domains", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListPagesDomainsHandler)
+				cloudflare.POST("/pages/projects/:projectname/domains", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.AddPagesDomainHandler)
+				cloudflare.GET("/pages/projects/:projectname/domains/:domainname", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetPagesDomainHandler)
+				cloudflare.POST("/pages/projects/:projectname/domains/:domainname/verify", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.VerifyPagesDomainHandler)
+				cloudflare.DELETE("/pages/projects/:projectname/domains/:domainname", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DeletePagesDomainHandler)
 				cloudflare.GET("/kv/namespaces", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetKVNamespacesHandler)
+				cloudflare.POST("/kv/namespaces", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.CreateKVNamespaceHandler)
+				cloudflare.PUT("/kv/namespaces", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RenameKVNamespaceHandler)
+				cloudflare.DELETE("/kv/namespaces", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DeleteKVNamespaceHandler)
+				cloudflare.GET("/kv/namespaces/:namespaceid/export", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ExportKVNamespaceHandler)
+				cloudflare.POST("/kv/namespaces/:namespaceid/import", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ImportKVNamespaceHandler)
 				cloudflare.POST("/kv/namespaces/keys", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetKVKeysHandler)
 				cloudflare.POST("/kv/namespaces/keys/values", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetKVKeyValuesHandler)
 				cloudflare.PUT("/kv/namespaces/keys/values", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.UpdateKVKeyValuesHandler)
+				cloudflare.POST("/kv/namespaces/keys/history", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListKVHistoryHandler)
+				cloudflare.POST("/kv/namespaces/keys/history/diff", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DiffKVHistoryHandler)
+				cloudflare.POST("/kv/namespaces/keys/history/restore", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RestoreKVHistoryHandler)
+				cloudflare.PUT("/kv/namespaces/bulk", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.BulkUpdateKVKeyValuesHandler)
+				cloudflare.DELETE("/kv/namespaces/bulk", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.BulkDeleteKVKeysHandler)
+				cloudflare.POST("/kv/promote", handler.PromoteKVHandler)
+				cloudflare.GET("/release", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListReleasesHandler)
+				cloudflare.POST("/release", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ReleaseHandler)
+				cloudflare.POST("/release/rollback", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RollbackReleaseHandler)
 				cloudflare.POST("/bucketinfo", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.GetBucketHandler)
 				cloudflare.DELETE("/bucketinfo", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DeleteDirectoryHandler)
 				cloudflare.POST("/bucketinfo/copy", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.CopyDirectoryHandler)
+				cloudflare.GET("/r2/objects", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListR2ObjectsHandler)
+				cloudflare.POST("/r2/objects/upload", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.UploadR2ObjectHandler)
+				cloudflare.POST("/r2/objects/archive", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.UploadR2ArchiveHandler)
+				cloudflare.GET("/r2/objects/download", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DownloadR2ObjectHandler)
+				cloudflare.POST("/r2/presign", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.PresignR2ObjectHandler)
+				cloudflare.POST("/r2/copy", handler.CopyR2ObjectsHandler)
+				cloudflare.POST("/r2/verify", handler.VerifyR2ObjectsHandler)
+				cloudflare.GET("/r2/trash", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListR2TrashHandler)
+				cloudflare.POST("/r2/trash/restore", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.RestoreR2TrashHandler)
+				cloudflare.POST("/r2/trash/purge", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.PurgeR2TrashHandler)
+				cloudflare.GET("/r2/retention", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ListR2RetentionPoliciesHandler)
+				cloudflare.PUT("/r2/retention", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.SaveR2RetentionPolicyHandler)
+				cloudflare.DELETE("/r2/retention", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.DeleteR2RetentionPolicyHandler)
+				cloudflare.POST("/r2/retention/preview", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.PreviewR2RetentionHandler)
+				cloudflare.POST("/r2/retention/execute", middleware.ValidateAndGetHeaders(middleware.CommonHeaders...), handler.ExecuteR2RetentionHandler)
+
+				// KV 值校验规则管理路由组
+				kvSchemas := cloudflare.Group("/kv/schemas")
+				{
+					kvSchemas.GET("", handler.ListKVValueSchemasHandler)
+					kvSchemas.POST("", handler.CreateKVValueSchemaHandler)
+					kvSchemas.PUT("/:id", handler.UpdateKVValueSchemaHandler)
+					kvSchemas.DELETE("/:id", handler.DeleteKVValueSchemaHandler)
+					kvSchemas.POST("/validate", handler.ValidateKVValueHandler)
+				}
 
 				// Cloudflare账号管理路由组
 				accounts := cloudflare.Group("/accounts")
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"sync"
 	"time"
@@ -46,24 +49,32 @@ func GetDefaultClient() *CloudflareClient {
 }
 
 // LoadConfig 加载配置
+// 默认客户端为所有请求共享，并发加载不同环境的配置会互相覆盖，请求中应使用 loadCloudflareClient
 func (c *CloudflareClient) LoadConfig(env, countryCode string) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-
-	// 加载 Cloudflare 配置
-	var cloudflareConfig model.CloudflareAccountInfo
-	if err := db.DB.Where("environment = ? AND is_active = ?", env, true).First(&cloudflareConfig).Error; err != nil {
-		return fmt.Errorf("failed to load cloudflare config: %v", err)
+	cloudflareConfig, err := loadCloudflareConfig(env)
+	if err != nil {
+		return err
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	c.config = &CFClientConfig{
-		CloudflareConfig: &cloudflareConfig,
+		CloudflareConfig: cloudflareConfig,
 	}
 
 	logger.Info("Loaded Cloudflare config for env: %s, country: %s", env, countryCode)
 	return nil
 }
 
+// loadCloudflareConfig 加载环境当前启用的 Cloudflare 账号配置
+func loadCloudflareConfig(env string) (*model.CloudflareAccountInfo, error) {
+	var cloudflareConfig model.CloudflareAccountInfo
+	if err := db.DB.Where("environment = ? AND is_active = ?", env, true).First(&cloudflareConfig).Error; err != nil {
+		return nil, fmt.Errorf("failed to load cloudflare config: %v", err)
+	}
+	return &cloudflareConfig, nil
+}
+
 // GetConfig 获取当前配置
 func (c *CloudflareClient) GetConfig() *CFClientConfig {
 	c.mutex.RLock()
@@ -71,17 +82,88 @@ func (c *CloudflareClient) GetConfig() *CFClientConfig {
 	return c.config
 }
 
+// loadCloudflareClient 加载环境的账号配置，返回绑定该账号的客户端和账号 ID
+// 返回的客户端与默认客户端共用连接池，配置只属于本次调用，可以在多个 goroutine 中并发使用
+func loadCloudflareClient(countryCode, env string) (*CloudflareClient, string, error) {
+	cloudflareConfig, err := loadCloudflareConfig(env)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load config: %v", err)
+	}
+
+	logger.Info("Loaded Cloudflare config for env: %s, country: %s", env, countryCode)
+
+	client := &CloudflareClient{
+		httpClient: GetDefaultClient().httpClient,
+		config:     &CFClientConfig{CloudflareConfig: cloudflareConfig},
+	}
+	return client, cloudflareConfig.AccountID, nil
+}
+
 // Do 执行 HTTP 请求
 func (c *CloudflareClient) Do(req *http.Request) (*http.Response, error) {
 	// 添加通用请求头
-	if c.config != nil && c.config.CloudflareConfig != nil {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.config.CloudflareConfig.ApiToken))
-		req.Header.Set("Content-Type", "application/json")
+	if config := c.GetConfig(); config != nil && config.CloudflareConfig != nil {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.CloudflareConfig.ApiToken))
+		// 保留调用方设置的 Content-Type（如 text/plain、multipart/form-data）
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
 	}
 
 	return c.httpClient.Do(req)
 }
 
+// DoJSON 发送 JSON 请求并将响应解析到 out，payload 为 nil 时不发送请求体
+// 非 2xx 状态码或 success 为 false 时返回错误
+func (c *CloudflareClient) DoJSON(method, url string, payload, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var status struct {
+		Success bool          `json:"success"`
+		Errors  []interface{} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	if !status.Success {
+		return fmt.Errorf("API request failed: %v", status.Errors)
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return fmt.Errorf("failed to parse response: %v", err)
+		}
+	}
+	return nil
+}
+
 // Reset 重置客户端配置
 func (c *CloudflareClient) Reset() {
 	c.mutex.Lock()
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+)
+
+// kvBulkSender 发送单个分批请求，返回该批次的执行结果
+type kvBulkSender func(client *CloudflareClient, accountID string, start, end int) (*constants.KVBulkWriteResult, error)
+
+// BulkWriteKVKeyValues 批量写入 KV 键值对，超过单次上限时自动分批
+// 每个批次写入前读取该批次 key 的旧值，写入成功后记录变更历史（配置 kv_history_key_patterns 时只记录匹配的 key）
+func BulkWriteKVKeyValues(countryCode, env, namespaceId string, pairs []constants.KVBulkPair, operator string) (*constants.KVBulkResult, error) {
+	values := make(map[string]string, len(pairs))
+	keys := make([]string, len(pairs))
+	for i, pair := range pairs {
+		if pair.Key == "" {
+			return nil, fmt.Errorf("pairs[%d]: key is required", i)
+		}
+		opts := KVWriteOptions{
+			Metadata:      pair.Metadata,
+			Expiration:    pair.Expiration,
+			ExpirationTTL: pair.ExpirationTTL,
+		}
+		if err := opts.validate(); err != nil {
+			return nil, fmt.Errorf("pairs[%d] (%s): %v", i, pair.Key, err)
+		}
+		value, err := decodeKVBulkValue(pair)
+		if err != nil {
+			return nil, fmt.Errorf("pairs[%d] (%s): %v", i, pair.Key, err)
+		}
+		values[pair.Key] = value
+		keys[i] = pair.Key
+	}
+
+	// 写入前按已注册的规则校验所有值，任一 key 未通过则整体拒绝
+	if err := ValidateKVValues(namespaceId, values); err != nil {
+		return nil, err
+	}
+
+	historyKeys, err := selectKVHistoryKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Bulk writing %d KV pairs for countryCode: %s, env: %s, namespace: %s", len(pairs), countryCode, env, namespaceId)
+
+	return runKVBulk(countryCode, env, len(pairs), func(client *CloudflareClient, accountID string, start, end int) (*constants.KVBulkWriteResult, error) {
+		chunk := pairs[start:end]
+		var chunkHistoryKeys []string
+		written := make(map[string]kvValueSnapshot)
+		for _, pair := range chunk {
+			if !historyKeys[pair.Key] {
+				continue
+			}
+			chunkHistoryKeys = append(chunkHistoryKeys, pair.Key)
+			written[pair.Key] = writtenKVSnapshot(values[pair.Key], KVWriteOptions{
+				Metadata:      pair.Metadata,
+				Expiration:    pair.Expiration,
+				ExpirationTTL: pair.ExpirationTTL,
+			})
+		}
+
+		snapshots, err := snapshotKVKeyValues(client, accountID, namespaceId, chunkHistoryKeys, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read current values: %v", err)
+		}
+
+		url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/bulk", constants.CFBaseURL, accountID, namespaceId)
+		result, err := sendKVBulkRequest(client, "PUT", url, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		recordKVBulkHistory(countryCode, env, namespaceId, model.KVOperationUpdate, operator, chunkHistoryKeys, snapshots, written, result)
+		return result, nil
+	})
+}
+
+// BulkDeleteKVKeys 批量删除 KV keys，超过单次上限时自动分批
+// 每个批次删除前读取该批次 key 的旧值，删除成功后记录变更历史（配置 kv_history_key_patterns 时只记录匹配的 key）
+func BulkDeleteKVKeys(countryCode, env, namespaceId string, keys []string, operator string) (*constants.KVBulkResult, error) {
+	for i, key := range keys {
+		if key == "" {
+			return nil, fmt.Errorf("keys[%d]: key is required", i)
+		}
+	}
+
+	historyKeys, err := selectKVHistoryKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Bulk deleting %d KV keys for countryCode: %s, env: %s, namespace: %s", len(keys), countryCode, env, namespaceId)
+
+	return runKVBulk(countryCode, env, len(keys), func(client *CloudflareClient, accountID string, start, end int) (*constants.KVBulkWriteResult, error) {
+		chunk := keys[start:end]
+		var chunkHistoryKeys []string
+		for _, key := range chunk {
+			if historyKeys[key] {
+				chunkHistoryKeys = append(chunkHistoryKeys, key)
+			}
+		}
+
+		snapshots, err := snapshotKVKeyValues(client, accountID, namespaceId, chunkHistoryKeys, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read current values: %v", err)
+		}
+
+		url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/bulk/delete", constants.CFBaseURL, accountID, namespaceId)
+		result, err := sendKVBulkRequest(client, "POST", url, chunk)
+		if err != nil {
+			return nil, err
+		}
+
+		recordKVBulkHistory(countryCode, env, namespaceId, model.KVOperationDelete, operator, chunkHistoryKeys, snapshots, nil, result)
+		return result, nil
+	})
+}
+
+// decodeKVBulkValue 返回键值对的原始值，base64 编码的值会被解码
+func decodeKVBulkValue(pair constants.KVBulkPair) (string, error) {
+	if !pair.Base64 {
+		return pair.Value, nil
+	}
+	decoded, err := base64.StdEncoding.DecodeString(pair.Value)
+	if err != nil {
+		return "", fmt.Errorf("invalid base64 value: %v", err)
+	}
+	return string(decoded), nil
+}
+
+// recordKVBulkHistory 为批次中执行成功的 key 记录变更历史，written 为 nil 表示删除
+func recordKVBulkHistory(countryCode, env, namespaceId, operation, operator string, keys []string, snapshots map[string]kvValueSnapshot, written map[string]kvValueSnapshot, result *constants.KVBulkWriteResult) {
+	failed := make(map[string]bool, len(result.UnsuccessfulKeys))
+	for _, key := range result.UnsuccessfulKeys {
+		failed[key] = true
+	}
+
+	records := make([]model.KVValueHistory, 0, len(keys))
+	for _, key := range keys {
+		if failed[key] {
+			continue
+		}
+		var after *kvValueSnapshot
+		if snapshot, ok := written[key]; ok {
+			after = &snapshot
+		}
+		records = append(records, newKVValueHistory(countryCode, env, namespaceId, key, operation, operator, snapshots[key], after))
+	}
+	recordKVHistories(records)
+}
+
+// runKVBulk 按 KVBulkMaxItems 分批执行，单个批次失败不影响后续批次
+func runKVBulk(countryCode, env string, total int, send kvBulkSender) (*constants.KVBulkResult, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &constants.KVBulkResult{
+		Total:  total,
+		Chunks: make([]constants.KVBulkChunkResult, 0),
+	}
+
+	for index, start := 0, 0; start < total; index, start = index+1, start+constants.KVBulkMaxItems {
+		end := start + constants.KVBulkMaxItems
+		if end > total {
+			end = total
+		}
+
+		chunk := constants.KVBulkChunkResult{
+			Index:  index,
+			Offset: start,
+			Size:   end - start,
+		}
+
+		resp, err := send(client, accountID, start, end)
+		if err != nil {
+			chunk.Error = err.Error()
+			logger.Error("KV bulk chunk %d (offset %d, size %d) failed: %v", index, start, chunk.Size, err)
+		} else {
+			chunk.SuccessfulKeyCount = resp.SuccessfulKeyCount
+			chunk.UnsuccessfulKeys = resp.UnsuccessfulKeys
+			// 旧版接口不返回统计信息，成功即视为全部写入
+			if chunk.SuccessfulKeyCount == 0 && len(chunk.UnsuccessfulKeys) == 0 {
+				chunk.SuccessfulKeyCount = chunk.Size
+			}
+			chunk.Success = len(chunk.UnsuccessfulKeys) == 0
+			logger.Info("KV bulk chunk %d (offset %d, size %d) done, successful: %d, unsuccessful: %d",
+				index, start, chunk.Size, chunk.SuccessfulKeyCount, len(chunk.UnsuccessfulKeys))
+		}
+
+		result.Succeeded += chunk.SuccessfulKeyCount
+		result.Chunks = append(result.Chunks, chunk)
+	}
+
+	result.Failed = result.Total - result.Succeeded
+	return result, nil
+}
+
+// sendKVBulkRequest 发送 KV 批量请求
+func sendKVBulkRequest(client *CloudflareClient, method, url string, payload interface{}) (*constants.KVBulkWriteResult, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var cfResp constants.CFSingleResponse[*constants.KVBulkWriteResult]
+	if err := json.Unmarshal(body, &cfResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	if !cfResp.Success {
+		return nil, fmt.Errorf("API request failed: %v", cfResp.Errors)
+	}
+
+	if cfResp.Result == nil {
+		return &constants.KVBulkWriteResult{}, nil
+	}
+	return cfResp.Result, nil
+}
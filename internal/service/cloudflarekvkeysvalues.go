@@ -1,105 +1,291 @@
 package service
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"openapi/internal/constants"
 	"openapi/internal/logger"
+	"openapi/internal/model"
+)
+
+// ErrKVKeyNotFound KV key 不存在
+var ErrKVKeyNotFound = errors.New("kv key not found")
+
+const (
+	// kvReadMaxAttempts KV 读取被限流（429）时的最大尝试次数
+	kvReadMaxAttempts = 5
+
+	// kvReadBackoffBase、kvReadBackoffMax 限流重试的初始和最长等待时间
+	kvReadBackoffBase = time.Second
+	kvReadBackoffMax  = 30 * time.Second
 )
 
 // GetKVKeyValues 获取 KV key的值
+// key 不存在时返回 ErrKVKeyNotFound
 func GetKVKeyValues(countryCode, env, namespaceId, keyName string) (*constants.CFRawResponse, error) {
-	// 获取默认客户端
-	client := GetDefaultClient()
-
-	// 加载配置
-	if err := client.LoadConfig(env, countryCode); err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
 	}
 
-	// 获取配置信息
-	config := client.GetConfig()
-	if config == nil || config.CloudflareConfig == nil {
-		return nil, fmt.Errorf("cloudflare config not loaded")
-	}
+	logger.Info("Getting KV key value for countryCode: %s, env: %s, namespace: %s, key: %s", countryCode, env, namespaceId, keyName)
+
+	return readKVKeyValue(client, accountID, namespaceId, keyName)
+}
 
+// readKVKeyValue 使用已加载配置的客户端读取 KV key的值
+func readKVKeyValue(client *CloudflareClient, accountID, namespaceId, keyName string) (*constants.CFRawResponse, error) {
 	// 构建请求 URL
-	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/values/%s", constants.CFBaseURL, config.CloudflareConfig.AccountID, namespaceId, keyName)
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/values/%s", constants.CFBaseURL, accountID, namespaceId, neturl.PathEscape(keyName))
 
-	// 创建请求
-	req, err := http.NewRequest("GET", url, nil)
+	resp, body, err := doKVRead(client, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %v", err)
+		return nil, err
 	}
 
-	// 添加认证头
-	// req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", authToken))
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrKVKeyNotFound, keyName)
+	}
 
-	logger.Info("Getting KV key value for countryCode: %s, env: %s, namespace: %s, key: %s", countryCode, env, namespaceId, keyName)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
 
-	resp, err := client.Do(req)
+	// 读取过期时间（仅当 key 设置了过期时间时返回）
+	var expiration int64
+	if v := resp.Header.Get("Expiration"); v != "" {
+		expiration, _ = strconv.ParseInt(v, 10, 64)
+	}
+
+	// 成功时响应体即为原始值，JSON 格式的值也按原样返回
+	return &constants.CFRawResponse{
+		RawData:    string(body),
+		Expiration: expiration,
+	}, nil
+}
+
+// GetKVKeyMetadata 获取 KV key的元数据
+func GetKVKeyMetadata(countryCode, env, namespaceId, keyName string) (json.RawMessage, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
+	logger.Info("Getting KV key metadata for countryCode: %s, env: %s, namespace: %s, key: %s", countryCode, env, namespaceId, keyName)
+
+	return readKVKeyMetadata(client, accountID, namespaceId, keyName)
+}
+
+// readKVKeyMetadata 使用已加载配置的客户端读取 KV key的元数据，未设置元数据时返回 nil
+// key 不存在时返回 ErrKVKeyNotFound
+func readKVKeyMetadata(client *CloudflareClient, accountID, namespaceId, keyName string) (json.RawMessage, error) {
+	// 构建请求 URL
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/metadata/%s", constants.CFBaseURL, accountID, namespaceId, neturl.PathEscape(keyName))
+
+	resp, body, err := doKVRead(client, url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrKVKeyNotFound, keyName)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
 	}
 
-	// 尝试解析为 JSON 格式
-	var jsonResp constants.CFResponse[constants.KVKeysValues]
-	if err := json.Unmarshal(body, &jsonResp); err != nil {
-		// 如果不是 JSON 格式，直接返回原始字符串
-		return &constants.CFRawResponse{
-			RawData: string(body),
-		}, nil
+	var cfResp constants.CFSingleResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &cfResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
 	}
 
-	// 如果是 JSON 格式但解析失败
-	if !jsonResp.Success {
-		return nil, fmt.Errorf("API request failed: %v", jsonResp.Errors)
+	if !cfResp.Success {
+		return nil, fmt.Errorf("API request failed: %v", cfResp.Errors)
 	}
 
-	// 如果是 JSON 格式且成功解析，转换为原始响应
-	return &constants.CFRawResponse{
-		RawData: jsonResp.Result[0].Value,
-	}, nil
+	// 未设置元数据时 Cloudflare 返回 null
+	if string(cfResp.Result) == "null" {
+		return nil, nil
+	}
+
+	return cfResp.Result, nil
+}
+
+// doKVRead 发送 KV 读取请求并读取响应体，被限流时按 Retry-After 或指数退避重试
+func doKVRead(client *CloudflareClient, url string) (*http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		// 创建请求
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %v", err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to send request: %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= kvReadMaxAttempts {
+			return resp, body, nil
+		}
+
+		delay := kvReadBackoff(attempt, resp.Header.Get("Retry-After"))
+		logger.Info("KV read rate limited, retrying in %s (attempt %d/%d)", delay, attempt, kvReadMaxAttempts)
+		time.Sleep(delay)
+	}
+}
+
+// kvReadBackoff 第 attempt 次被限流后的等待时间，优先使用 Retry-After，否则指数增长并带随机抖动
+func kvReadBackoff(attempt int, retryAfter string) time.Duration {
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds > 0 {
+		if delay := time.Duration(seconds) * time.Second; delay < kvReadBackoffMax {
+			return delay
+		}
+		return kvReadBackoffMax
+	}
+	delay := kvReadBackoffBase << uint(attempt-1)
+	if delay > kvReadBackoffMax || delay <= 0 {
+		delay = kvReadBackoffMax
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// KVWriteOptions KV 写入参数
+type KVWriteOptions struct {
+	Metadata      json.RawMessage // key 元数据（JSON），非空时使用 multipart 写入
+	Expiration    int64           // 过期时间（Unix 秒）
+	ExpirationTTL int64           // 过期秒数，最小 60
+	Operator      string          // 操作人，记录到变更历史
+
+	operation string // 变更历史中的操作类型，默认为 update
+}
+
+// validate 校验写入参数
+func (o KVWriteOptions) validate() error {
+	if o.Expiration > 0 && o.ExpirationTTL > 0 {
+		return fmt.Errorf("expiration and expiration_ttl cannot be set at the same time")
+	}
+	if o.ExpirationTTL > 0 && o.ExpirationTTL < constants.KVMinExpirationTTL {
+		return fmt.Errorf("expiration_ttl must be at least %d seconds", constants.KVMinExpirationTTL)
+	}
+	if len(o.Metadata) > 0 {
+		if !json.Valid(o.Metadata) {
+			return fmt.Errorf("metadata must be valid JSON")
+		}
+		if len(o.Metadata) > constants.KVMaxMetadataSize {
+			return fmt.Errorf("metadata exceeds %d bytes", constants.KVMaxMetadataSize)
+		}
+	}
+	return nil
+}
+
+// TagKVMetadata 在元数据中写入操作人和操作时间
+// 元数据必须为 JSON 对象或为空
+func TagKVMetadata(metadata json.RawMessage, operator string) (json.RawMessage, error) {
+	fields := make(map[string]interface{})
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := json.Unmarshal(metadata, &fields); err != nil {
+			return nil, fmt.Errorf("metadata must be a JSON object: %v", err)
+		}
+	}
+
+	fields["updated_by"] = operator
+	fields["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+
+	return json.Marshal(fields)
 }
 
 // UpdateKVKeyValues 更新 KV key的值
-func UpdateKVKeyValues(countryCode, env, namespaceId, keyName string, value string) (*constants.CFResponse[constants.UpdateKVKeysValues], error) {
-	// 获取默认客户端
-	client := GetDefaultClient()
+// 写入前会读取旧值并记录变更历史；ProdVersion key 在指定操作人时会在元数据中记录设置人和设置时间
+func UpdateKVKeyValues(countryCode, env, namespaceId, keyName string, value string, opts KVWriteOptions) (*constants.CFResponse[constants.UpdateKVKeysValues], error) {
+	// ProdVersion 为发布版本指针，记录设置人和设置时间
+	if opts.Operator != "" && strings.Contains(keyName, constants.KVProdVersionKey) {
+		tagged, err := TagKVMetadata(opts.Metadata, opts.Operator)
+		if err != nil {
+			return nil, err
+		}
+		opts.Metadata = tagged
+	}
 
-	// 加载配置
-	if err := client.LoadConfig(env, countryCode); err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+	if err := opts.validate(); err != nil {
+		return nil, err
 	}
 
-	// 获取配置信息
-	config := client.GetConfig()
-	if config == nil || config.CloudflareConfig == nil {
-		return nil, fmt.Errorf("cloudflare config not loaded")
+	// 写入前按已注册的规则校验值
+	if err := ValidateKVValue(namespaceId, keyName, value); err != nil {
+		return nil, err
 	}
 
-	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/values/%s", constants.CFBaseURL, config.CloudflareConfig.AccountID, namespaceId, keyName)
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
 
-	req, err := http.NewRequest("PUT", url, strings.NewReader(value))
+	// 过期参数通过查询参数传递
+	query := neturl.Values{}
+	if opts.Expiration > 0 {
+		query.Set("expiration", strconv.FormatInt(opts.Expiration, 10))
+	}
+	if opts.ExpirationTTL > 0 {
+		query.Set("expiration_ttl", strconv.FormatInt(opts.ExpirationTTL, 10))
+	}
+
+	// 写入前读取旧值，用于记录变更历史
+	old, err := snapshotKVKeyValue(client, accountID, namespaceId, keyName, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current value: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/values/%s", constants.CFBaseURL, accountID, namespaceId, neturl.PathEscape(keyName))
+	if len(query) > 0 {
+		url += "?" + query.Encode()
+	}
+
+	// 带元数据时使用 multipart 写入，否则直接写入原始值
+	var reqBody io.Reader = strings.NewReader(value)
+	contentType := "text/plain"
+	if len(opts.Metadata) > 0 {
+		buf := &bytes.Buffer{}
+		writer := multipart.NewWriter(buf)
+		if err := writer.WriteField("value", value); err != nil {
+			return nil, fmt.Errorf("failed to write value field: %v", err)
+		}
+		if err := writer.WriteField("metadata", string(opts.Metadata)); err != nil {
+			return nil, fmt.Errorf("failed to write metadata field: %v", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to close multipart writer: %v", err)
+		}
+		reqBody = buf
+		contentType = writer.FormDataContentType()
+	}
+
+	req, err := http.NewRequest("PUT", url, reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Content-Type", contentType)
+
+	logger.Info("Updating KV key value for countryCode: %s, env: %s, namespace: %s, key: %s, with metadata: %t, expiration: %d, expiration_ttl: %d",
+		countryCode, env, namespaceId, keyName, len(opts.Metadata) > 0, opts.Expiration, opts.ExpirationTTL)
 
 	resp, err := client.Do(req)
 	if err != nil {
@@ -121,5 +307,61 @@ func UpdateKVKeyValues(countryCode, env, namespaceId, keyName string, value stri
 		return nil, fmt.Errorf("failed to parse response: %v", err)
 	}
 
+	operation := opts.operation
+	if operation == "" {
+		operation = model.KVOperationUpdate
+	}
+	written := writtenKVSnapshot(value, opts)
+	record := newKVValueHistory(countryCode, env, namespaceId, keyName, operation, opts.Operator, old, &written)
+	recordKVHistory(&record)
+
 	return &cfResp, nil
 }
+
+// DeleteKVKey 删除 KV key
+func DeleteKVKey(countryCode, env, namespaceId, keyName, operator string) error {
+	return deleteKVKey(countryCode, env, namespaceId, keyName, operator, model.KVOperationDelete)
+}
+
+// deleteKVKey 删除 KV key 并以指定操作类型记录变更历史
+func deleteKVKey(countryCode, env, namespaceId, keyName, operator, operation string) error {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return err
+	}
+
+	// 删除前读取旧值，用于记录变更历史
+	old, err := snapshotKVKeyValue(client, accountID, namespaceId, keyName, true)
+	if err != nil {
+		return fmt.Errorf("failed to read current value: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/values/%s", constants.CFBaseURL, accountID, namespaceId, neturl.PathEscape(keyName))
+
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+
+	logger.Info("Deleting KV key for countryCode: %s, env: %s, namespace: %s, key: %s", countryCode, env, namespaceId, keyName)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	record := newKVValueHistory(countryCode, env, namespaceId, keyName, operation, operator, old, nil)
+	recordKVHistory(&record)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// KVNamespaceInUseError KV 命名空间仍被 Pages 项目绑定
+type KVNamespaceInUseError struct {
+	NamespaceID string   `json:"namespace_id"`
+	Projects    []string `json:"projects"`
+}
+
+func (e *KVNamespaceInUseError) Error() string {
+	return fmt.Sprintf("KV namespace %s is still bound by Pages projects: %s",
+		e.NamespaceID, strings.Join(e.Projects, ", "))
+}
+
+// kvNamespaceRequest 创建或重命名 KV 命名空间的请求体
+type kvNamespaceRequest struct {
+	Title string `json:"title"`
+}
+
+// CreateKVNamespace 创建 KV 命名空间
+func CreateKVNamespace(countryCode, env, title string) (*constants.KVNamespace, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, fmt.Errorf("title is required")
+	}
+
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces", constants.CFBaseURL, accountID)
+
+	logger.Info("Creating KV namespace %s for account: %s", title, accountID)
+
+	var cfResp constants.CFSingleResponse[constants.KVNamespace]
+	if err := client.DoJSON("POST", url, kvNamespaceRequest{Title: title}, &cfResp); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Created KV namespace %s (%s)", cfResp.Result.Title, cfResp.Result.ID)
+	return &cfResp.Result, nil
+}
+
+// RenameKVNamespace 重命名 KV 命名空间
+func RenameKVNamespace(countryCode, env, namespaceId, title string) error {
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("title is required")
+	}
+
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s", constants.CFBaseURL, accountID, namespaceId)
+
+	logger.Info("Renaming KV namespace %s to %s for account: %s", namespaceId, title, accountID)
+
+	return client.DoJSON("PUT", url, kvNamespaceRequest{Title: title}, nil)
+}
+
+// DeleteKVNamespace 删除 KV 命名空间
+// 仍有 Pages 项目绑定该命名空间时返回 *KVNamespaceInUseError，force 为 true 时跳过检查
+func DeleteKVNamespace(countryCode, env, namespaceId string, force bool) error {
+	if !force {
+		projects, err := findKVNamespaceProjects(countryCode, env, namespaceId)
+		if err != nil {
+			return fmt.Errorf("failed to check namespace bindings: %v", err)
+		}
+		if len(projects) > 0 {
+			return &KVNamespaceInUseError{NamespaceID: namespaceId, Projects: projects}
+		}
+	}
+
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s", constants.CFBaseURL, accountID, namespaceId)
+
+	logger.Info("Deleting KV namespace %s for account: %s (force: %t)", namespaceId, accountID, force)
+
+	return client.DoJSON("DELETE", url, nil, nil)
+}
+
+// findKVNamespaceProjects 返回预览或生产环境绑定了该 KV 命名空间的 Pages 项目名称
+func findKVNamespaceProjects(countryCode, env, namespaceId string) ([]string, error) {
+	pagesResp, err := GetPagesProject(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var projects []string
+	for _, project := range pagesResp.Result {
+		if bindsKVNamespace(project.Deployment_configs.Production, namespaceId) ||
+			bindsKVNamespace(project.Deployment_configs.Preview, namespaceId) {
+			projects = append(projects, project.Name)
+		}
+	}
+	return projects, nil
+}
+
+// bindsKVNamespace 判断部署配置中是否有绑定该命名空间的 KV 绑定
+func bindsKVNamespace(deploymentConfig constants.DeploymentConfig, namespaceId string) bool {
+	for _, binding := range deploymentConfig.KV_namespaces {
+		if binding.Namespace_ID == namespaceId {
+			return true
+		}
+	}
+	return false
+}
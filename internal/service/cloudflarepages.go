@@ -5,31 +5,63 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"openapi/internal/config"
 	"openapi/internal/constants"
 	"openapi/internal/logger"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
-// GetPagesProject 获取 Pages 项目列表
+// GetPagesProject 获取 Pages 项目列表，遍历所有分页
 func GetPagesProject(countryCode, env string) (*constants.CFResponse[constants.PagesProject], error) {
-	// 获取默认客户端
-	client := GetDefaultClient()
-
-	// 加载配置
-	if err := client.LoadConfig(env, countryCode); err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
 	}
 
-	// 获取配置信息
-	config := client.GetConfig()
-	if config == nil || config.CloudflareConfig == nil {
-		return nil, fmt.Errorf("cloudflare config not loaded")
+	logger.Info("Getting Pages projects for account: %s", accountID)
+
+	return listPagesProjects(client, accountID)
+}
+
+// listPagesProjects 使用已加载配置的客户端获取所有分页的 Pages 项目
+func listPagesProjects(client *CloudflareClient, accountID string) (*constants.CFResponse[constants.PagesProject], error) {
+	var result constants.CFResponse[constants.PagesProject]
+	result.Result = make([]constants.PagesProject, 0)
+	for page := 1; ; page++ {
+		pageResp, err := listPagesProjectsPage(client, accountID, page)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Success = pageResp.Success
+		result.Errors = pageResp.Errors
+		result.Messages = pageResp.Messages
+		result.Result = append(result.Result, pageResp.Result...)
+
+		// 没有分页信息或已到最后一页时结束
+		info := pageResp.ResultInfo
+		if len(pageResp.Result) == 0 || info == nil {
+			break
+		}
+		if info.TotalPages > 0 && page >= info.TotalPages {
+			break
+		}
+		if info.TotalPages == 0 && (info.TotalCount == 0 || len(result.Result) >= info.TotalCount) {
+			break
+		}
 	}
 
+	result.ResultInfo = &constants.CFResultInfo{Count: len(result.Result), TotalCount: len(result.Result)}
+	return &result, nil
+}
+
+// listPagesProjectsPage 获取单页 Pages 项目
+func listPagesProjectsPage(client *CloudflareClient, accountID string, page int) (*constants.CFResponse[constants.PagesProject], error) {
 	// 构建请求 URL
-	url := fmt.Sprintf("%s/%s/pages/projects",
-		constants.CFBaseURL,
-		config.CloudflareConfig.AccountID)
+	url := fmt.Sprintf("%s/%s/pages/projects?page=%d", constants.CFBaseURL, accountID, page)
 
 	// 创建请求
 	req, err := http.NewRequest("GET", url, nil)
@@ -37,8 +69,6 @@ func GetPagesProject(countryCode, env string) (*constants.CFResponse[constants.P
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	logger.Info("Getting Pages projects for account: %s", config.CloudflareConfig.AccountID)
-
 	// 执行请求
 	resp, err := client.Do(req)
 	if err != nil {
@@ -66,26 +96,85 @@ func GetPagesProject(countryCode, env string) (*constants.CFResponse[constants.P
 	return &cfResp, nil
 }
 
-// GetKVNamespaces 获取 KV 命名空间列表
+// GetPagesProjectByName 获取单个 Pages 项目
+func GetPagesProjectByName(countryCode, env, projectName string) (*constants.PagesProject, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s/pages/projects/%s", constants.CFBaseURL, accountID, url.PathEscape(projectName))
+
+	var cfResp constants.CFSingleResponse[constants.PagesProject]
+	if err := client.DoJSON("GET", reqURL, nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp.Result, nil
+}
+
+// kvNamespacesPerPage 每页读取的 KV 命名空间数量（Cloudflare 允许的最大值）
+const kvNamespacesPerPage = 100
+
+// GetKVNamespaces 获取 KV 命名空间列表（自动翻页读取全部命名空间）
 func GetKVNamespaces(countryCode, env string) (*constants.CFResponse[constants.KVNamespace], error) {
-	// 获取默认客户端
-	client := GetDefaultClient()
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting KV namespaces for account: %s", accountID)
+	return listKVNamespaces(client, accountID)
+}
 
-	// 加载配置
-	if err := client.LoadConfig(env, countryCode); err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+// listKVNamespaces 使用已加载配置的客户端获取所有分页的 KV 命名空间，任何一页失败都返回错误
+func listKVNamespaces(client *CloudflareClient, accountID string) (*constants.CFResponse[constants.KVNamespace], error) {
+	var result constants.CFResponse[constants.KVNamespace]
+	result.Result = make([]constants.KVNamespace, 0)
+	totalCount := 0
+	for page := 1; ; page++ {
+		pageResp, err := listKVNamespacesPage(client, accountID, page)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Success = pageResp.Success
+		result.Errors = pageResp.Errors
+		result.Messages = pageResp.Messages
+		result.Result = append(result.Result, pageResp.Result...)
+
+		// 没有分页信息或已到最后一页时结束
+		info := pageResp.ResultInfo
+		if info != nil && info.TotalCount > totalCount {
+			totalCount = info.TotalCount
+		}
+		if len(pageResp.Result) == 0 || info == nil {
+			break
+		}
+		if info.TotalPages > 0 && page >= info.TotalPages {
+			break
+		}
+		if info.TotalPages == 0 && (info.TotalCount == 0 || len(result.Result) >= info.TotalCount) {
+			break
+		}
 	}
 
-	// 获取配置信息
-	config := client.GetConfig()
-	if config == nil || config.CloudflareConfig == nil {
-		return nil, fmt.Errorf("cloudflare config not loaded")
+	// 读到的数量少于接口报告的总数时说明列表不完整
+	if len(result.Result) < totalCount {
+		return nil, fmt.Errorf("incomplete KV namespace list: got %d of %d namespaces", len(result.Result), totalCount)
 	}
 
+	result.ResultInfo = &constants.CFResultInfo{Count: len(result.Result), TotalCount: len(result.Result)}
+	return &result, nil
+}
+
+// listKVNamespacesPage 获取单页 KV 命名空间
+func listKVNamespacesPage(client *CloudflareClient, accountID string, page int) (*constants.CFResponse[constants.KVNamespace], error) {
 	// 构建请求 URL
-	url := fmt.Sprintf("%s/%s/storage/kv/namespaces",
+	url := fmt.Sprintf("%s/%s/storage/kv/namespaces?page=%d&per_page=%d",
 		constants.CFBaseURL,
-		config.CloudflareConfig.AccountID)
+		accountID,
+		page,
+		kvNamespacesPerPage)
 
 	// 创建请求
 	req, err := http.NewRequest("GET", url, nil)
@@ -93,8 +182,6 @@ func GetKVNamespaces(countryCode, env string) (*constants.CFResponse[constants.K
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	logger.Info("Getting KV namespaces for account: %s", config.CloudflareConfig.AccountID)
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
@@ -118,36 +205,84 @@ func GetKVNamespaces(countryCode, env string) (*constants.CFResponse[constants.K
 	return &cfResp, nil
 }
 
+// KVKeysListOptions KV keys 列表查询参数
+type KVKeysListOptions struct {
+	Prefix string // 只返回以该前缀开头的 key
+	Limit  int    // 单页数量，范围 10-1000，0 表示使用 Cloudflare 默认值
+	Cursor string // 上一页返回的游标
+	All    bool   // 为 true 时从 Cursor 开始遍历所有分页
+}
+
 // GetKVKeys 获取 KV keys列表
-func GetKVKeys(countryCode, env, namespaceId string) (*constants.CFResponse[constants.KVKeys], error) {
-	// 获取默认客户端
-	client := GetDefaultClient()
+// 返回结果中的 ResultInfo.Cursor 为下一页游标，为空表示已无更多数据
+func GetKVKeys(countryCode, env, namespaceId string, opts KVKeysListOptions) (*constants.CFResponse[constants.KVKeys], error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting KV keys for account: %s, namespace: %s, prefix: %s, cursor: %s, all: %t",
+		accountID, namespaceId, opts.Prefix, opts.Cursor, opts.All)
 
-	// 加载配置
-	if err := client.LoadConfig(env, countryCode); err != nil {
-		return nil, fmt.Errorf("failed to load config: %v", err)
+	if !opts.All {
+		return listKVKeysPage(client, accountID, namespaceId, opts)
 	}
 
-	// 获取配置信息
-	config := client.GetConfig()
-	if config == nil || config.CloudflareConfig == nil {
-		return nil, fmt.Errorf("cloudflare config not loaded")
+	// 遍历所有分页
+	var result constants.CFResponse[constants.KVKeys]
+	result.Result = make([]constants.KVKeys, 0)
+	for {
+		page, err := listKVKeysPage(client, accountID, namespaceId, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Success = page.Success
+		result.Errors = page.Errors
+		result.Messages = page.Messages
+		result.Result = append(result.Result, page.Result...)
+
+		if page.ResultInfo == nil || page.ResultInfo.Cursor == "" {
+			break
+		}
+		opts.Cursor = page.ResultInfo.Cursor
+	}
+
+	result.ResultInfo = &constants.CFResultInfo{Count: len(result.Result)}
+	logger.Info("Listed %d KV keys in namespace %s", len(result.Result), namespaceId)
+
+	return &result, nil
+}
+
+// listKVKeysPage 获取单页 KV keys
+func listKVKeysPage(client *CloudflareClient, accountID, namespaceId string, opts KVKeysListOptions) (*constants.CFResponse[constants.KVKeys], error) {
+	// 构建查询参数
+	query := url.Values{}
+	if opts.Prefix != "" {
+		query.Set("prefix", opts.Prefix)
+	}
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		query.Set("cursor", opts.Cursor)
 	}
 
 	// 构建请求 URL
-	url := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/keys",
+	reqURL := fmt.Sprintf("%s/%s/storage/kv/namespaces/%s/keys",
 		constants.CFBaseURL,
-		config.CloudflareConfig.AccountID,
+		accountID,
 		namespaceId)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
 
 	// 创建请求
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest("GET", reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
-	logger.Info("Getting KV keys for account: %s, namespace: %s", config.CloudflareConfig.AccountID, namespaceId)
-
 	resp, err := client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %v", err)
@@ -171,61 +306,117 @@ func GetKVKeys(countryCode, env, namespaceId string) (*constants.CFResponse[cons
 	return &cfResp, nil
 }
 
-// CombinePageProjectKVNamespacesAndKeys 组合所有三种数据结构
-func CombinePageProjectKVNamespacesAndKeys(pagesResp *constants.CFResponse[constants.PagesProject], kvResp *constants.CFResponse[constants.KVNamespace], countryCode, env string) error {
-	var filteredProjects []constants.PagesProject
+// DefaultKVKeyPatterns 未配置 kv_key_patterns 时使用的 key 通配符
+var DefaultKVKeyPatterns = []string{"*" + constants.KVProdVersionKey + "*"}
 
-	// 遍历所有 Pages 项目
-	for i := range pagesResp.Result {
-		project := pagesResp.Result[i]
-		namespaceID := project.Deployment_configs.Production.KV_namespaces.Kv.Namespace_ID
-
-		// 遍历所有 KV 命名空间
-		for _, namespace := range kvResp.Result {
-			// 当 namespace_id 和 id 相同时，检查 KV Keys
-			if namespace.ID == namespaceID && namespaceID != "" {
-				// 获取该命名空间的 KV Keys
-				keysResp, err := GetKVKeys(countryCode, env, namespaceID)
-				if err != nil {
-					return fmt.Errorf("failed to get KV keys for namespace %s: %v", namespaceID, err)
+// KVKeyPatterns 返回配置的 KV key 通配符
+func KVKeyPatterns() []string {
+	if patterns := config.GlobalConfig.Cloudflare.KVKeyPatterns; len(patterns) > 0 {
+		return patterns
+	}
+	return DefaultKVKeyPatterns
+}
+
+// compileKeyPatterns 将 key 通配符转换为正则，* 匹配任意字符（包括 /），? 匹配单个字符
+func compileKeyPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		expr := regexp.QuoteMeta(pattern)
+		expr = strings.ReplaceAll(expr, `\*`, ".*")
+		expr = strings.ReplaceAll(expr, `\?`, ".")
+		re, err := regexp.Compile("^" + expr + "$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// matchesAnyPattern 判断 key 是否匹配任一通配符
+func matchesAnyPattern(patterns []*regexp.Regexp, key string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// CombinePageProjectKVNamespacesAndKeys 为所有项目预览和生产环境的 KV 绑定补充命名空间信息和匹配 keyPatterns 的 key
+func CombinePageProjectKVNamespacesAndKeys(pagesResp *constants.CFResponse[constants.PagesProject], kvResp *constants.CFResponse[constants.KVNamespace], countryCode, env string, keyPatterns []string) error {
+	patterns, err := compileKeyPatterns(keyPatterns)
+	if err != nil {
+		return err
+	}
+
+	namespaces := make(map[string]constants.KVNamespace, len(kvResp.Result))
+	for _, namespace := range kvResp.Result {
+		namespaces[namespace.ID] = namespace
+	}
+
+	// 同一命名空间可能被多个项目或环境绑定，只读取一次
+	matchedKeys := make(map[string][]constants.KVKeys)
+	getMatchedKeys := func(namespaceID string) ([]constants.KVKeys, error) {
+		if keys, ok := matchedKeys[namespaceID]; ok {
+			return keys, nil
+		}
+		keysResp, err := GetKVKeys(countryCode, env, namespaceID, KVKeysListOptions{All: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get KV keys for namespace %s: %v", namespaceID, err)
+		}
+		var keys []constants.KVKeys
+		for _, key := range keysResp.Result {
+			for _, re := range patterns {
+				if re.MatchString(key.Name) {
+					keys = append(keys, key)
+					break
 				}
+			}
+		}
+		matchedKeys[namespaceID] = keys
+		return keys, nil
+	}
 
-				// 检查是否有包含 ProdVersion 的键
-				var prodVersionKeys []constants.KVKeys
-				for _, key := range keysResp.Result {
-					if strings.Contains(key.Name, "ProdVersion") {
-						prodVersionKeys = append(prodVersionKeys, key)
-					}
+	for i := range pagesResp.Result {
+		project := &pagesResp.Result[i]
+		for _, deploymentConfig := range []*constants.DeploymentConfig{
+			&project.Deployment_configs.Preview,
+			&project.Deployment_configs.Production,
+		} {
+			for name, binding := range deploymentConfig.KV_namespaces {
+				namespace, ok := namespaces[binding.Namespace_ID]
+				if !ok {
+					logger.Info("KV namespace %s bound as %s in project %s not found in account",
+						binding.Namespace_ID, name, project.Name)
+					continue
 				}
 
-				// 如果找到包含 ProdVersion 的键
-				if len(prodVersionKeys) > 0 {
-					// 更新项目信息
-					project.Deployment_configs.Production.KV_namespaces.Kv = constants.KVInfo{
-						Namespace_ID:        namespaceID,
-						Title:               namespace.Title,
-						SupportsURLEncoding: namespace.SupportsUrlEncoding,
-						Keys:                prodVersionKeys, // 保留所有包含 ProdVersion 的键
-						HasProdVersion:      true,
-					}
-
-					// 添加到过滤后的项目列表
-					filteredProjects = append(filteredProjects, project)
-					logger.Info("Found project with ProdVersion keys - Project: %s, Namespace: %s, Keys count: %d",
-						project.Name, namespace.Title, len(prodVersionKeys))
+				keys, err := getMatchedKeys(binding.Namespace_ID)
+				if err != nil {
+					return err
 				}
-				break
+
+				binding.Title = namespace.Title
+				binding.SupportsURLEncoding = namespace.SupportsUrlEncoding
+				binding.Keys = keys
+				binding.HasProdVersion = len(keys) > 0
+				deploymentConfig.KV_namespaces[name] = binding
 			}
 		}
+		logger.Info("Combined bindings for project %s: %d production KV, %d preview KV",
+			project.Name, len(project.Deployment_configs.Production.KV_namespaces), len(project.Deployment_configs.Preview.KV_namespaces))
 	}
 
-	// 用过滤后的项目替换原始结果
-	pagesResp.Result = filteredProjects
 	return nil
 }
 
-// GetPagesProjectWithKVNamespacesAndKeys 获取完整的组合数据
-func GetPagesProjectWithKVNamespacesAndKeys(countryCode, env string) (*constants.CFResponse[constants.PagesProject], error) {
+// GetPagesProjectWithKVNamespacesAndKeys 获取完整的组合数据，keyPatterns 为空时使用配置的 key 通配符
+func GetPagesProjectWithKVNamespacesAndKeys(countryCode, env string, keyPatterns []string) (*constants.CFResponse[constants.PagesProject], error) {
+	if len(keyPatterns) == 0 {
+		keyPatterns = KVKeyPatterns()
+	}
+
 	// 获取 Pages 项目数据
 	pagesResp, err := GetPagesProject(countryCode, env)
 	if err != nil {
@@ -239,7 +430,7 @@ func GetPagesProjectWithKVNamespacesAndKeys(countryCode, env string) (*constants
 	}
 
 	// 组合所有数据
-	if err := CombinePageProjectKVNamespacesAndKeys(pagesResp, kvResp, countryCode, env); err != nil {
+	if err := CombinePageProjectKVNamespacesAndKeys(pagesResp, kvResp, countryCode, env, keyPatterns); err != nil {
 		return nil, fmt.Errorf("failed to combine data: %v", err)
 	}
 
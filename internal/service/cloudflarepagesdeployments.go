@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// Pages 部署环境
+const (
+	PagesEnvProduction = "production"
+	PagesEnvPreview    = "preview"
+)
+
+// 构建日志流轮询间隔及最长持续时间
+const (
+	pagesLogPollInterval = 3 * time.Second
+	pagesLogStreamMaxAge = 30 * time.Minute
+)
+
+// Pages 构建日志流事件
+const (
+	PagesLogEventLog    = "log"    // 新的日志行
+	PagesLogEventStatus = "status" // 部署阶段变化
+	PagesLogEventDone   = "done"   // 部署结束，data 为最终的部署信息
+)
+
+// PagesDeploymentListOptions 部署列表查询参数
+type PagesDeploymentListOptions struct {
+	Environment string // production 或 preview，为空时返回全部
+	Page        int
+	PerPage     int
+}
+
+// ListPagesDeployments 获取 Pages 项目的部署列表，按创建时间倒序
+func ListPagesDeployments(countryCode, env, projectName string, opts PagesDeploymentListOptions) (*constants.CFResponse[constants.PagesDeployment], error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if opts.Environment != "" {
+		query.Set("env", opts.Environment)
+	}
+	if opts.Page > 0 {
+		query.Set("page", strconv.Itoa(opts.Page))
+	}
+	if opts.PerPage > 0 {
+		query.Set("per_page", strconv.Itoa(opts.PerPage))
+	}
+
+	reqURL := pagesDeploymentsURL(accountID, projectName)
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
+
+	logger.Info("Getting deployments of Pages project %s for account: %s, environment: %s",
+		projectName, accountID, opts.Environment)
+
+	var cfResp constants.CFResponse[constants.PagesDeployment]
+	if err := client.DoJSON("GET", reqURL, nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp, nil
+}
+
+// GetPagesDeployment 获取部署详情
+func GetPagesDeployment(countryCode, env, projectName, deploymentId string) (*constants.PagesDeployment, error) {
+	return doPagesDeployment(countryCode, env, "GET", projectName, deploymentId, "")
+}
+
+// RollbackPagesDeployment 将生产环境回滚到指定的历史部署
+func RollbackPagesDeployment(countryCode, env, projectName, deploymentId string) (*constants.PagesDeployment, error) {
+	return doPagesDeployment(countryCode, env, "POST", projectName, deploymentId, "rollback")
+}
+
+// RetryPagesDeployment 重试失败的部署
+func RetryPagesDeployment(countryCode, env, projectName, deploymentId string) (*constants.PagesDeployment, error) {
+	return doPagesDeployment(countryCode, env, "POST", projectName, deploymentId, "retry")
+}
+
+// doPagesDeployment 对单个部署执行操作，action 为空时获取部署详情
+func doPagesDeployment(countryCode, env, method, projectName, deploymentId, action string) (*constants.PagesDeployment, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s", pagesDeploymentsURL(accountID, projectName), url.PathEscape(deploymentId))
+	if action != "" {
+		reqURL += "/" + action
+		logger.Info("Running %s on deployment %s of Pages project %s for account: %s",
+			action, deploymentId, projectName, accountID)
+	}
+
+	var cfResp constants.CFSingleResponse[constants.PagesDeployment]
+	if err := client.DoJSON(method, reqURL, nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp.Result, nil
+}
+
+// pagesDeploymentsURL 返回 Pages 项目部署接口地址
+func pagesDeploymentsURL(accountID, projectName string) string {
+	return fmt.Sprintf("%s/%s/pages/projects/%s/deployments",
+		constants.CFBaseURL,
+		accountID,
+		url.PathEscape(projectName))
+}
+
+// GetPagesDeploymentLogs 获取部署的构建日志
+func GetPagesDeploymentLogs(countryCode, env, projectName, deploymentId string) (*constants.PagesDeploymentLogs, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s/history/logs", pagesDeploymentsURL(accountID, projectName), url.PathEscape(deploymentId))
+
+	var cfResp constants.CFSingleResponse[constants.PagesDeploymentLogs]
+	if err := client.DoJSON("GET", reqURL, nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp.Result, nil
+}
+
+// IsPagesDeploymentRunning 判断部署是否仍在进行中
+// 部署按 queued、initialize、clone_repo、build、deploy 顺序执行，最后一个阶段成功或任一阶段失败、取消时结束
+func IsPagesDeploymentRunning(deployment *constants.PagesDeployment) bool {
+	stage := deployment.LatestStage
+	switch stage.Status {
+	case "failure", "canceled", "skipped":
+		return false
+	case "success":
+		return stage.Name != "deploy"
+	}
+	return true
+}
+
+// StreamPagesDeploymentLogs 轮询部署状态和构建日志，通过 emit 推送新日志行和阶段变化，部署结束或 ctx 取消时返回
+func StreamPagesDeploymentLogs(ctx context.Context, countryCode, env, projectName, deploymentId string, emit func(event string, data interface{}) error) error {
+	ctx, cancel := context.WithTimeout(ctx, pagesLogStreamMaxAge)
+	defer cancel()
+
+	ticker := time.NewTicker(pagesLogPollInterval)
+	defer ticker.Stop()
+
+	sent := 0
+	var lastStage constants.PagesDeploymentStage
+	for {
+		deployment, err := GetPagesDeployment(countryCode, env, projectName, deploymentId)
+		if err != nil {
+			return err
+		}
+
+		logs, err := GetPagesDeploymentLogs(countryCode, env, projectName, deploymentId)
+		if err != nil {
+			return err
+		}
+		// 只推送上次之后新增的日志行
+		if sent > len(logs.Data) {
+			sent = 0
+		}
+		for _, line := range logs.Data[sent:] {
+			if err := emit(PagesLogEventLog, line); err != nil {
+				return err
+			}
+		}
+		sent = len(logs.Data)
+
+		if deployment.LatestStage.Name != lastStage.Name || deployment.LatestStage.Status != lastStage.Status {
+			lastStage = deployment.LatestStage
+			if err := emit(PagesLogEventStatus, lastStage); err != nil {
+				return err
+			}
+		}
+
+		if !IsPagesDeploymentRunning(deployment) {
+			logger.Info("Deployment %s of Pages project %s finished with stage %s: %s",
+				deploymentId, projectName, lastStage.Name, lastStage.Status)
+			return emit(PagesLogEventDone, deployment)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
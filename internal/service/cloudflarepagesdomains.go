@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// pagesDomainsWorkers 汇总所有项目域名时的并发数
+const pagesDomainsWorkers = 5
+
+// PagesProjectDomains 单个 Pages 项目的自定义域名
+type PagesProjectDomains struct {
+	ProjectName string                  `json:"project_name"`
+	Subdomain   string                  `json:"subdomain"`
+	Domains     []constants.PagesDomain `json:"domains"`
+	Error       string                  `json:"error,omitempty"`
+}
+
+// ListPagesDomains 获取 Pages 项目的自定义域名列表
+func ListPagesDomains(countryCode, env, projectName string) ([]constants.PagesDomain, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Getting domains of Pages project %s for account: %s", projectName, accountID)
+
+	return listPagesDomains(client, accountID, projectName)
+}
+
+// listPagesDomains 使用已加载配置的客户端获取 Pages 项目的自定义域名列表
+func listPagesDomains(client *CloudflareClient, accountID, projectName string) ([]constants.PagesDomain, error) {
+	var cfResp constants.CFResponse[constants.PagesDomain]
+	if err := client.DoJSON("GET", pagesDomainsURL(accountID, projectName), nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return cfResp.Result, nil
+}
+
+// AddPagesDomain 为 Pages 项目添加自定义域名
+func AddPagesDomain(countryCode, env, projectName, domainName string) (*constants.PagesDomain, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Adding domain %s to Pages project %s for account: %s", domainName, projectName, accountID)
+
+	var cfResp constants.CFSingleResponse[constants.PagesDomain]
+	payload := map[string]string{"name": domainName}
+	if err := client.DoJSON("POST", pagesDomainsURL(accountID, projectName), payload, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp.Result, nil
+}
+
+// GetPagesDomain 获取自定义域名及其验证状态
+func GetPagesDomain(countryCode, env, projectName, domainName string) (*constants.PagesDomain, error) {
+	return doPagesDomain(countryCode, env, "GET", projectName, domainName)
+}
+
+// RetryPagesDomainValidation 重新触发自定义域名验证
+func RetryPagesDomainValidation(countryCode, env, projectName, domainName string) (*constants.PagesDomain, error) {
+	return doPagesDomain(countryCode, env, "PATCH", projectName, domainName)
+}
+
+// DeletePagesDomain 删除 Pages 项目的自定义域名
+func DeletePagesDomain(countryCode, env, projectName, domainName string) error {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Deleting domain %s from Pages project %s for account: %s", domainName, projectName, accountID)
+
+	reqURL := fmt.Sprintf("%s/%s", pagesDomainsURL(accountID, projectName), url.PathEscape(domainName))
+	return client.DoJSON("DELETE", reqURL, nil, nil)
+}
+
+// ListAllPagesDomains 汇总账号下所有 Pages 项目的自定义域名，单个项目获取失败不影响其他项目
+// 账号配置只加载一次，所有 worker 共用同一个客户端
+func ListAllPagesDomains(countryCode, env string) ([]PagesProjectDomains, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	pagesResp, err := listPagesProjects(client, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages project: %v", err)
+	}
+
+	logger.Info("Getting domains of %d Pages projects for account: %s", len(pagesResp.Result), accountID)
+
+	results := make([]PagesProjectDomains, len(pagesResp.Result))
+	tasks := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < pagesDomainsWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range tasks {
+				project := pagesResp.Result[index]
+				result := PagesProjectDomains{
+					ProjectName: project.Name,
+					Subdomain:   project.Subdomain,
+				}
+				domains, err := listPagesDomains(client, accountID, project.Name)
+				if err != nil {
+					logger.Error("Failed to get domains of Pages project %s: %v", project.Name, err)
+					result.Error = err.Error()
+				}
+				result.Domains = domains
+				results[index] = result
+			}
+		}()
+	}
+
+	for i := range pagesResp.Result {
+		tasks <- i
+	}
+	close(tasks)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].ProjectName < results[j].ProjectName
+	})
+	return results, nil
+}
+
+// doPagesDomain 对单个自定义域名执行请求
+func doPagesDomain(countryCode, env, method, projectName, domainName string) (*constants.PagesDomain, error) {
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s", pagesDomainsURL(accountID, projectName), url.PathEscape(domainName))
+
+	var cfResp constants.CFSingleResponse[constants.PagesDomain]
+	if err := client.DoJSON(method, reqURL, nil, &cfResp); err != nil {
+		return nil, err
+	}
+	return &cfResp.Result, nil
+}
+
+// pagesDomainsURL 返回 Pages 项目自定义域名接口地址
+func pagesDomainsURL(accountID, projectName string) string {
+	return fmt.Sprintf("%s/%s/pages/projects/%s/domains",
+		constants.CFBaseURL,
+		accountID,
+		url.PathEscape(projectName))
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"sync"
 
 	"openapi/internal/constants"
 	"openapi/internal/logger"
+	"openapi/internal/model"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -15,14 +15,22 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
-// createR2Client 创建 R2 客户端
-func createR2Client() (*s3.Client, error) {
-	// 获取配置（使用缓存）
-	config, err := GetCloudflareConfig()
+// createR2ClientFor 按环境和国家创建 R2 客户端，同时返回所用的账号
+func createR2ClientFor(env, countryCode string) (*s3.Client, *model.CloudflareAccountInfo, error) {
+	account, err := loadCloudflareAccount(env, countryCode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get cloudflare config: %v", err)
+		return nil, nil, err
 	}
 
+	client, err := newR2Client(account)
+	if err != nil {
+		return nil, nil, err
+	}
+	return client, account, nil
+}
+
+// newR2Client 使用指定账号创建 R2 客户端
+func newR2Client(config *model.CloudflareAccountInfo) (*s3.Client, error) {
 	// 创建 AWS 配置
 	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(),
 		awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
@@ -47,7 +55,7 @@ func createR2Client() (*s3.Client, error) {
 
 // GetBucketInfo 获取 bucket 目录信息
 func GetBucketInfo(countryCode, env, bucketName string) (*constants.CFResponse[constants.KVKeys], error) {
-	client, err := createR2Client()
+	client, _, err := createR2ClientFor(env, countryCode)
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +87,7 @@ func GetBucketInfo(countryCode, env, bucketName string) (*constants.CFResponse[c
 		}
 
 		// 检查是否符合条件（包含 "latest" 或以 "v" 开头）
-		if name == "latest" || strings.HasPrefix(name, "v") {
+		if isVersionDirectory(name) {
 			filteredResult = append(filteredResult, constants.KVKeys{Name: name})
 			seenNames[name] = true
 			logger.Info("Found matching item - Name: %s", name)
@@ -117,176 +125,16 @@ func GetBucketInfo(countryCode, env, bucketName string) (*constants.CFResponse[c
 	return &result, nil
 }
 
-// CopyTask 表示一个复制任务
-type CopyTask struct {
-	SourceKey string
-	TargetKey string
+// isVersionDirectory 判断目录名是否为版本目录（latest 或以 v 开头）
+func isVersionDirectory(name string) bool {
+	return name == "latest" || strings.HasPrefix(name, "v")
 }
 
-// CopyDirectory 复制目录及其内容到新位置
-func CopyDirectory(countryCode, Env, bucketName, sourceDir, targetDir string) error {
-	client, err := createR2Client()
+// r2DirectoryHasObjects 检查环境账号下的目录是否至少有一个对象，目录不存在时返回 false
+func r2DirectoryHasObjects(countryCode, env, bucketName, dirPath string) (bool, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
 	if err != nil {
-		return err
-	}
-
-	// 确保源目录和目标目录以 / 结尾
-	sourceDir = strings.TrimSuffix(sourceDir, "/") + "/"
-	targetDir = strings.TrimSuffix(targetDir, "/") + "/"
-
-	// 创建任务通道和错误通道
-	tasks := make(chan CopyTask, 100)
-	errors := make(chan error, 1)
-	var wg sync.WaitGroup
-
-	// 启动工作池
-	workerCount := 50 // 并发工作器数量
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for task := range tasks {
-				// 创建复制源引用
-				copySource := fmt.Sprintf("%s/%s", bucketName, task.SourceKey)
-
-				// 复制对象
-				_, err := client.CopyObject(context.TODO(), &s3.CopyObjectInput{
-					Bucket:     aws.String(bucketName),
-					CopySource: aws.String(copySource),
-					Key:        aws.String(task.TargetKey),
-				})
-				if err != nil {
-					select {
-					case errors <- fmt.Errorf("failed to copy %s to %s: %v",
-						task.SourceKey, task.TargetKey, err):
-					default:
-					}
-					return
-				}
-
-				logger.Info("Copied %s to %s", task.SourceKey, task.TargetKey)
-			}
-		}()
+		return false, err
 	}
-
-	// 列出源目录中的所有对象并创建任务
-	go func() {
-		defer close(tasks)
-		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
-			Bucket: aws.String(bucketName),
-			Prefix: aws.String(sourceDir),
-		})
-
-		for paginator.HasMorePages() {
-			page, err := paginator.NextPage(context.TODO())
-			if err != nil {
-				errors <- fmt.Errorf("failed to list objects: %v", err)
-				return
-			}
-
-			for _, obj := range page.Contents {
-				sourceKey := *obj.Key
-				targetKey := targetDir + strings.TrimPrefix(sourceKey, sourceDir)
-				tasks <- CopyTask{
-					SourceKey: sourceKey,
-					TargetKey: targetKey,
-				}
-			}
-		}
-	}()
-
-	// 等待所有工作完成
-	wg.Wait()
-
-	// 检查是否有错误
-	select {
-	case err := <-errors:
-		return err
-	default:
-	}
-
-	logger.Info("Successfully copied directory from %s to %s", sourceDir, targetDir)
-	return nil
-}
-
-// DeleteTask 表示一个删除任务
-type DeleteTask struct {
-	Key string
-}
-
-// DeleteDirectory 删除目录及其内容
-func DeleteDirectory(countryCode, Env, bucketName, dirPath string) error {
-	client, err := createR2Client()
-	if err != nil {
-		return err
-	}
-
-	// 确保目录路径以 / 结尾
-	dirPath = strings.TrimSuffix(dirPath, "/") + "/"
-
-	// 创建任务通道和错误通道
-	tasks := make(chan DeleteTask, 100)
-	errors := make(chan error, 1)
-	var wg sync.WaitGroup
-
-	// 启动工作池
-	workerCount := 50 // 并发工作器数量
-	for i := 0; i < workerCount; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for task := range tasks {
-				// 删除对象
-				_, err := client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
-					Bucket: aws.String(bucketName),
-					Key:    aws.String(task.Key),
-				})
-				if err != nil {
-					select {
-					case errors <- fmt.Errorf("failed to delete object %s: %v", task.Key, err):
-					default:
-					}
-					return
-				}
-
-				logger.Info("Deleted %s", task.Key)
-			}
-		}()
-	}
-
-	// 列出目录中的所有对象并创建删除任务
-	go func() {
-		defer close(tasks)
-		paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
-			Bucket: aws.String(bucketName),
-			Prefix: aws.String(dirPath),
-		})
-
-		for paginator.HasMorePages() {
-			page, err := paginator.NextPage(context.TODO())
-			if err != nil {
-				errors <- fmt.Errorf("failed to list objects: %v", err)
-				return
-			}
-
-			for _, obj := range page.Contents {
-				tasks <- DeleteTask{
-					Key: *obj.Key,
-				}
-			}
-		}
-	}()
-
-	// 等待所有工作完成
-	wg.Wait()
-
-	// 检查是否有错误
-	select {
-	case err := <-errors:
-		return err
-	default:
-	}
-
-	logger.Info("Successfully deleted directory %s", dirPath)
-	return nil
+	return r2PrefixHasObjects(context.TODO(), client, bucketName, strings.TrimSuffix(dirPath, "/")+"/")
 }
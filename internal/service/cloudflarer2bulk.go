@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"openapi/internal/config"
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// R2 批量操作中单个 key 的处理结果
+const (
+	R2KeySucceeded = "succeeded"
+	R2KeyFailed    = "failed"
+	R2KeySkipped   = "skipped" // 未处理：源对象已不存在，或操作被取消
+)
+
+const (
+	// DefaultR2BulkConcurrency 未配置 r2_bulk_concurrency 时的并发数
+	DefaultR2BulkConcurrency = 50
+
+	// DefaultR2BulkMaxRetries 未配置 r2_bulk_max_retries 时临时错误的最大重试次数
+	DefaultR2BulkMaxRetries = 3
+
+	// MaxR2BulkConcurrency 单次批量操作允许的最大并发数
+	MaxR2BulkConcurrency = 200
+
+	r2BulkBackoffBase = 200 * time.Millisecond
+	r2BulkBackoffMax  = 5 * time.Second
+)
+
+// errR2BulkSkip 操作返回该错误时 key 记为跳过而不是失败
+var errR2BulkSkip = errors.New("skipped")
+
+// R2BulkOptions 批量操作参数，零值使用配置或默认值
+type R2BulkOptions struct {
+	Concurrency int
+	MaxRetries  int
+}
+
+// withDefaults 补全未指定的参数
+func (o R2BulkOptions) withDefaults() R2BulkOptions {
+	if o.Concurrency <= 0 {
+		o.Concurrency = config.GlobalConfig.Cloudflare.R2BulkConcurrency
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultR2BulkConcurrency
+	}
+	if o.Concurrency > MaxR2BulkConcurrency {
+		o.Concurrency = MaxR2BulkConcurrency
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = config.GlobalConfig.Cloudflare.R2BulkMaxRetries
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = DefaultR2BulkMaxRetries
+	}
+	return o
+}
+
+// R2KeyResult 单个 key 的处理结果
+type R2KeyResult struct {
+	Key      string `json:"key"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// R2BulkSummary 批量操作汇总
+type R2BulkSummary struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	Skipped   int           `json:"skipped"`
+	Bytes     int64         `json:"bytes"` // 成功处理的对象总大小
+	Results   []R2KeyResult `json:"results"`
+}
+
+// r2BulkItem 等待处理的对象
+type r2BulkItem struct {
+	Key          string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// newR2BulkItem 从列表结果创建待处理对象
+func newR2BulkItem(object types.Object) r2BulkItem {
+	return r2BulkItem{
+		Key:          aws.ToString(object.Key),
+		Size:         aws.ToInt64(object.Size),
+		ETag:         aws.ToString(object.ETag),
+		LastModified: aws.ToTime(object.LastModified),
+	}
+}
+
+// runR2Bulk 并发处理 items 中的所有对象直到通道关闭，临时错误按指数退避重试
+// 每个 key 都会得到一条结果；ctx 取消后剩余的 key 记为跳过
+func runR2Bulk(ctx context.Context, items <-chan r2BulkItem, opts R2BulkOptions, op func(ctx context.Context, item r2BulkItem) error) *R2BulkSummary {
+	opts = opts.withDefaults()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		summary = &R2BulkSummary{Results: make([]R2KeyResult, 0)}
+	)
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range items {
+				result := R2KeyResult{Key: item.Key}
+				if ctx.Err() != nil {
+					result.Status = R2KeySkipped
+					result.Error = ctx.Err().Error()
+				} else {
+					result.Attempts, result.Status, result.Error = runR2BulkItem(ctx, item, opts.MaxRetries, op)
+				}
+
+				mu.Lock()
+				summary.add(result, item.Size)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	summary.sortResults()
+	return summary
+}
+
+// add 累计单个 key 的结果
+func (s *R2BulkSummary) add(result R2KeyResult, size int64) {
+	s.Total++
+	switch result.Status {
+	case R2KeySucceeded:
+		s.Succeeded++
+		s.Bytes += size
+	case R2KeyFailed:
+		s.Failed++
+	case R2KeySkipped:
+		s.Skipped++
+	}
+	s.Results = append(s.Results, result)
+}
+
+// sortResults 按 key 排序结果
+func (s *R2BulkSummary) sortResults() {
+	sort.Slice(s.Results, func(i, j int) bool {
+		return s.Results[i].Key < s.Results[j].Key
+	})
+}
+
+// runR2BulkItem 处理单个对象，返回尝试次数、状态和错误信息
+func runR2BulkItem(ctx context.Context, item r2BulkItem, maxRetries int, op func(ctx context.Context, item r2BulkItem) error) (int, string, string) {
+	for attempt := 1; ; attempt++ {
+		err := op(ctx, item)
+		switch {
+		case err == nil:
+			return attempt, R2KeySucceeded, ""
+		case errors.Is(err, errR2BulkSkip):
+			return attempt, R2KeySkipped, err.Error()
+		case ctx.Err() != nil:
+			return attempt, R2KeySkipped, ctx.Err().Error()
+		case attempt > maxRetries || !isTransientR2Error(err):
+			logger.Error("Failed to process %s after %d attempts: %v", item.Key, attempt, err)
+			return attempt, R2KeyFailed, err.Error()
+		}
+
+		logger.Info("Retrying %s after transient error: %v", item.Key, err)
+		if !sleepR2Backoff(ctx, attempt) {
+			return attempt, R2KeySkipped, ctx.Err().Error()
+		}
+	}
+}
+
+// r2BulkBackoff 第 attempt 次失败后的等待时间，指数增长并带随机抖动
+func r2BulkBackoff(attempt int) time.Duration {
+	delay := r2BulkBackoffBase << uint(attempt-1)
+	if delay > r2BulkBackoffMax || delay <= 0 {
+		delay = r2BulkBackoffMax
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// isTransientR2Error 判断错误是否可以重试：限流、5xx 和连接错误
+func isTransientR2Error(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusTooManyRequests {
+		return true
+	}
+	return retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary
+}
+
+// isR2NoSuchKey 判断错误是否为对象不存在
+func isR2NoSuchKey(err error) bool {
+	var apiErr interface{ ErrorCode() string }
+	return errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey"
+}
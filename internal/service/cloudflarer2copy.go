@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// R2 跨桶复制方式
+const (
+	R2CopyServerSide = "server_side" // 同一账号内由 R2 服务端复制
+	R2CopyStream     = "stream"      // 跨账号时经本服务读取后写入
+)
+
+var (
+	// ErrR2PrefixOverlap 同一桶内源前缀与目标前缀重叠
+	ErrR2PrefixOverlap = errors.New("source and target prefixes overlap")
+
+	// ErrR2DeleteExtraUnsafe 目标为整个桶或源前缀下没有对象时不能清理目标中多余的对象
+	ErrR2DeleteExtraUnsafe = errors.New("refusing to delete extra objects")
+)
+
+// R2Location 表示某个环境下 R2 桶中的前缀
+type R2Location struct {
+	Env         string `json:"env"`
+	CountryCode string `json:"country_code"`
+	BucketName  string `json:"bucket"`
+	Prefix      string `json:"prefix"`
+}
+
+// R2CopyOptions 复制方式参数
+type R2CopyOptions struct {
+	Sync        bool // 增量同步：只复制目标中不存在或大小、ETag 不同的对象
+	DeleteExtra bool // 将目标前缀下源前缀中不存在的对象移入目标桶的回收站，仅在 Sync 时生效
+	Verify      bool // 复制完成后校验目标与源一致，不一致时任务失败
+}
+
+// CreateR2TransferJob 创建将源前缀复制到目标前缀的后台任务，两端可以属于不同环境、桶和 Cloudflare 账号
+// 同一账号使用服务端复制，不同账号时流式读取源对象并上传到目标
+// 同一账号同一桶内源前缀与目标前缀不能重叠
+func CreateR2TransferJob(source, target R2Location, operator string, opts R2BulkOptions, copyOpts R2CopyOptions) (*model.Job, error) {
+	source.Prefix = normalizeR2Prefix(source.Prefix)
+	target.Prefix = normalizeR2Prefix(target.Prefix)
+
+	sourceAccount, err := loadCloudflareAccount(source.Env, source.CountryCode)
+	if err != nil {
+		return nil, err
+	}
+	targetAccount, err := loadCloudflareAccount(target.Env, target.CountryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	sameAccount := sourceAccount.AccountID == targetAccount.AccountID
+	if sameAccount && source.BucketName == target.BucketName &&
+		(strings.HasPrefix(source.Prefix, target.Prefix) || strings.HasPrefix(target.Prefix, source.Prefix)) {
+		return nil, fmt.Errorf("%w: %q and %q in bucket %s",
+			ErrR2PrefixOverlap, source.Prefix, target.Prefix, source.BucketName)
+	}
+
+	if copyOpts.Sync && copyOpts.DeleteExtra {
+		client, _, err := createR2ClientFor(source.Env, source.CountryCode)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkR2DeleteExtra(context.TODO(), client, source.BucketName, source.Prefix, target.Prefix); err != nil {
+			return nil, err
+		}
+	}
+
+	mode := R2CopyStream
+	if sameAccount {
+		mode = R2CopyServerSide
+	}
+	logger.Info("Copying R2 objects from %s/%s/%s (%s) to %s/%s/%s (%s), mode: %s, sync: %t, delete extra: %t, verify: %t",
+		source.Env, source.BucketName, source.Prefix, sourceAccount.AccountID,
+		target.Env, target.BucketName, target.Prefix, targetAccount.AccountID, mode, copyOpts.Sync, copyOpts.DeleteExtra, copyOpts.Verify)
+
+	return createJob(&model.Job{
+		Type:          model.JobTypeR2Copy,
+		Environment:   source.Env,
+		CountryCode:   source.CountryCode,
+		BucketName:    source.BucketName,
+		SourceDir:     source.Prefix,
+		TargetDir:     target.Prefix,
+		TargetEnv:     target.Env,
+		TargetCountry: target.CountryCode,
+		TargetBucket:  target.BucketName,
+		Sync:          copyOpts.Sync,
+		DeleteExtra:   copyOpts.Sync && copyOpts.DeleteExtra,
+		Verify:        copyOpts.Verify,
+		Concurrency:   opts.Concurrency,
+		Operator:      operator,
+	})
+}
+
+// checkR2DeleteExtra 检查是否可以清理目标中多余的对象：目标前缀不能为空，源前缀下必须有对象
+// 避免空的或拼错的前缀把整个目标目录当作多余对象
+func checkR2DeleteExtra(ctx context.Context, sourceClient *s3.Client, sourceBucket, sourcePrefix, targetPrefix string) error {
+	if targetPrefix == "" {
+		return fmt.Errorf("%w: target prefix is empty", ErrR2DeleteExtraUnsafe)
+	}
+	exists, err := r2PrefixHasObjects(ctx, sourceClient, sourceBucket, sourcePrefix)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: source %s/%s is empty", ErrR2DeleteExtraUnsafe, sourceBucket, sourcePrefix)
+	}
+	return nil
+}
+
+// copyR2ObjectServerSide 在同一账号内复制对象，可以跨桶
+func copyR2ObjectServerSide(ctx context.Context, client *s3.Client, sourceBucket, sourceKey, targetBucket, targetKey string) error {
+	_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(targetBucket),
+		CopySource: aws.String(r2CopySource(sourceBucket, sourceKey)),
+		Key:        aws.String(targetKey),
+	})
+	if isR2NoSuchKey(err) {
+		return fmt.Errorf("%w: source %s/%s no longer exists", errR2BulkSkip, sourceBucket, sourceKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy %s/%s to %s/%s: %w", sourceBucket, sourceKey, targetBucket, targetKey, err)
+	}
+	return nil
+}
+
+// copyR2ObjectStream 从源账号读取对象并上传到目标账号，保留 Content-Type、Cache-Control、
+// Content-Encoding、Content-Disposition 和自定义元数据
+// 不超过一个分片大小的对象直接流式上传，只有更大的对象才占用分片缓冲区
+func copyR2ObjectStream(ctx context.Context, sourceClient, targetClient *s3.Client, sourceBucket, sourceKey, targetBucket, targetKey string) error {
+	output, err := sourceClient.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(sourceBucket),
+		Key:    aws.String(sourceKey),
+	})
+	if isR2NoSuchKey(err) {
+		return fmt.Errorf("%w: source %s/%s no longer exists", errR2BulkSkip, sourceBucket, sourceKey)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get object %s/%s: %w", sourceBucket, sourceKey, err)
+	}
+	defer output.Body.Close()
+
+	attrs := r2ObjectAttributes{
+		ContentType:        aws.ToString(output.ContentType),
+		CacheControl:       aws.ToString(output.CacheControl),
+		ContentEncoding:    aws.ToString(output.ContentEncoding),
+		ContentDisposition: aws.ToString(output.ContentDisposition),
+		Metadata:           output.Metadata,
+	}
+	if output.ContentLength != nil && *output.ContentLength <= constants.R2MultipartPartSize {
+		_, err = putR2ObjectSized(ctx, targetClient, targetBucket, targetKey, attrs, output.Body, *output.ContentLength)
+	} else {
+		_, err = putR2Object(ctx, targetClient, targetBucket, targetKey, attrs, output.Body)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to copy %s/%s to %s/%s: %w", sourceBucket, sourceKey, targetBucket, targetKey, err)
+	}
+	return nil
+}
+
+// r2CopySource 生成 CopyObject 的 CopySource，对 key 的每一段做 URL 编码
+func r2CopySource(bucketName, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return bucketName + "/" + strings.Join(segments, "/")
+}
+
+// normalizeR2Prefix 非空前缀统一以 / 结尾，空前缀表示整个桶
+func normalizeR2Prefix(prefix string) string {
+	prefix = strings.TrimPrefix(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
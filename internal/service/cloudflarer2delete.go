@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// R2DeleteBatchSize DeleteObjects 单次请求的最大 key 数量
+	R2DeleteBatchSize = 1000
+
+	// maxR2DeleteBatchConcurrency 同时进行的 DeleteObjects 请求数上限
+	maxR2DeleteBatchConcurrency = 8
+)
+
+// r2RetryableDeleteCodes DeleteObjects 中单个 key 可重试的错误码
+var r2RetryableDeleteCodes = map[string]bool{
+	"InternalError":      true,
+	"ServiceUnavailable": true,
+	"SlowDown":           true,
+}
+
+// R2DeletePreview 删除目录的预览（dry run）结果
+type R2DeletePreview struct {
+	Bucket  string `json:"bucket"`
+	DirPath string `json:"dir_path"`
+	Objects int64  `json:"objects"`
+	Bytes   int64  `json:"bytes"`
+}
+
+// PreviewDeleteDirectory 统计删除目录将会删除的对象数量和总大小，不做任何修改
+func PreviewDeleteDirectory(countryCode, env, bucketName, dirPath string) (*R2DeletePreview, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath = strings.TrimSuffix(dirPath, "/") + "/"
+	objects, bytes, err := countR2Objects(context.TODO(), client, bucketName, dirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Dry run delete of %s in bucket %s: %d objects, %d bytes", dirPath, bucketName, objects, bytes)
+	return &R2DeletePreview{
+		Bucket:  bucketName,
+		DirPath: dirPath,
+		Objects: objects,
+		Bytes:   bytes,
+	}, nil
+}
+
+// runR2BatchDelete 将 items 按 R2DeleteBatchSize 分批调用 DeleteObjects，返回每个 key 的结果
+// 请求失败时整批重试，单个 key 的临时错误只重试该 key
+func runR2BatchDelete(ctx context.Context, client *s3.Client, bucketName string, items <-chan r2BulkItem, opts R2BulkOptions) *R2BulkSummary {
+	opts = opts.withDefaults()
+	workers := opts.Concurrency
+	if workers > maxR2DeleteBatchConcurrency {
+		workers = maxR2DeleteBatchConcurrency
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		summary = &R2BulkSummary{Results: make([]R2KeyResult, 0)}
+		batches = make(chan []r2BulkItem)
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range batches {
+				results := deleteR2Batch(ctx, client, bucketName, batch, opts.MaxRetries)
+
+				mu.Lock()
+				for i, result := range results {
+					summary.add(result, batch[i].Size)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	batch := make([]r2BulkItem, 0, R2DeleteBatchSize)
+	for item := range items {
+		batch = append(batch, item)
+		if len(batch) == R2DeleteBatchSize {
+			batches <- batch
+			batch = make([]r2BulkItem, 0, R2DeleteBatchSize)
+		}
+	}
+	if len(batch) > 0 {
+		batches <- batch
+	}
+	close(batches)
+	wg.Wait()
+
+	summary.sortResults()
+	return summary
+}
+
+// deleteR2Batch 删除一批对象，返回与 batch 顺序一致的结果
+func deleteR2Batch(ctx context.Context, client *s3.Client, bucketName string, batch []r2BulkItem, maxRetries int) []R2KeyResult {
+	results := make([]R2KeyResult, len(batch))
+	pending := make([]int, len(batch))
+	for i, item := range batch {
+		results[i] = R2KeyResult{Key: item.Key}
+		pending[i] = i
+	}
+
+	// finish 将所有未完成的 key 标记为同一状态
+	finish := func(status, message string) {
+		for _, i := range pending {
+			results[i].Status = status
+			results[i].Error = message
+		}
+	}
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		if ctx.Err() != nil {
+			finish(R2KeySkipped, ctx.Err().Error())
+			break
+		}
+
+		objects := make([]types.ObjectIdentifier, 0, len(pending))
+		for _, i := range pending {
+			results[i].Attempts = attempt
+			objects = append(objects, types.ObjectIdentifier{Key: aws.String(batch[i].Key)})
+		}
+
+		output, err := client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucketName),
+			Delete: &types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true), // 只返回失败的 key
+			},
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				finish(R2KeySkipped, ctx.Err().Error())
+				break
+			}
+			if attempt > maxRetries || !isTransientR2Error(err) {
+				logger.Error("Failed to delete %d objects in bucket %s after %d attempts: %v", len(pending), bucketName, attempt, err)
+				finish(R2KeyFailed, fmt.Sprintf("failed to delete objects: %v", err))
+				break
+			}
+			if !sleepR2Backoff(ctx, attempt) {
+				finish(R2KeySkipped, ctx.Err().Error())
+				break
+			}
+			continue
+		}
+
+		keyErrors := make(map[string]types.Error, len(output.Errors))
+		for _, keyErr := range output.Errors {
+			keyErrors[aws.ToString(keyErr.Key)] = keyErr
+		}
+
+		retry := make([]int, 0)
+		for _, i := range pending {
+			keyErr, failed := keyErrors[batch[i].Key]
+			switch {
+			case !failed:
+				results[i].Status = R2KeySucceeded
+			case attempt <= maxRetries && r2RetryableDeleteCodes[aws.ToString(keyErr.Code)]:
+				retry = append(retry, i)
+			default:
+				results[i].Status = R2KeyFailed
+				results[i].Error = fmt.Sprintf("%s: %s", aws.ToString(keyErr.Code), aws.ToString(keyErr.Message))
+			}
+		}
+
+		pending = retry
+		if len(pending) > 0 && !sleepR2Backoff(ctx, attempt) {
+			finish(R2KeySkipped, ctx.Err().Error())
+			break
+		}
+	}
+
+	return results
+}
+
+// sleepR2Backoff 按退避时间等待，ctx 取消时返回 false
+func sleepR2Backoff(ctx context.Context, attempt int) bool {
+	select {
+	case <-time.After(r2BulkBackoff(attempt)):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// CreateR2CopyJob 创建复制目录的后台任务
+// 源目录和目标目录不能重叠，否则复制出的对象会被后续分页再次列出并复制到更深的目录，增量同步时还会误删源对象
+func CreateR2CopyJob(countryCode, env, bucketName, sourceDir, targetDir, operator string, opts R2BulkOptions, copyOpts R2CopyOptions) (*model.Job, error) {
+	sourceDir = normalizeR2Prefix(sourceDir)
+	targetDir = normalizeR2Prefix(targetDir)
+	if strings.HasPrefix(sourceDir, targetDir) || strings.HasPrefix(targetDir, sourceDir) {
+		return nil, fmt.Errorf("%w: %q and %q in bucket %s", ErrR2PrefixOverlap, sourceDir, targetDir, bucketName)
+	}
+	if copyOpts.Sync && copyOpts.DeleteExtra {
+		client, _, err := createR2ClientFor(env, countryCode)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkR2DeleteExtra(context.TODO(), client, bucketName, sourceDir, targetDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return createJob(&model.Job{
+		Type:        model.JobTypeR2Copy,
+		Environment: env,
+		CountryCode: countryCode,
+		BucketName:  bucketName,
+		SourceDir:   sourceDir,
+		TargetDir:   targetDir,
+		Sync:        copyOpts.Sync,
+		DeleteExtra: copyOpts.Sync && copyOpts.DeleteExtra,
+		Verify:      copyOpts.Verify,
+		Concurrency: opts.Concurrency,
+		Operator:    operator,
+	})
+}
+
+// CreateR2DeleteJob 创建删除目录的后台任务
+func CreateR2DeleteJob(countryCode, env, bucketName, dirPath, operator string, opts R2BulkOptions) (*model.Job, error) {
+	return createJob(&model.Job{
+		Type:        model.JobTypeR2Delete,
+		Environment: env,
+		CountryCode: countryCode,
+		BucketName:  bucketName,
+		SourceDir:   strings.TrimSuffix(dirPath, "/") + "/",
+		Concurrency: opts.Concurrency,
+		Operator:    operator,
+	})
+}
+
+// r2JobTarget 复制任务的目标端
+type r2JobTarget struct {
+	client     *s3.Client
+	bucketName string
+	serverSide bool // 与源同一账号，使用服务端复制
+}
+
+// newR2JobTarget 创建复制任务目标端的客户端，未指定目标环境和桶时与源相同
+func newR2JobTarget(job *model.Job, client *s3.Client, account *model.CloudflareAccountInfo) (*r2JobTarget, error) {
+	target := &r2JobTarget{client: client, bucketName: job.BucketName, serverSide: true}
+	if job.TargetBucket != "" {
+		target.bucketName = job.TargetBucket
+	}
+	if job.TargetEnv != "" && (job.TargetEnv != job.Environment || job.TargetCountry != job.CountryCode) {
+		targetClient, targetAccount, err := createR2ClientFor(job.TargetEnv, job.TargetCountry)
+		if err != nil {
+			return nil, err
+		}
+		target.client = targetClient
+		target.serverSide = targetAccount.AccountID == account.AccountID
+	}
+	return target, nil
+}
+
+// copy 将源对象复制到目标端的 targetKey
+func (t *r2JobTarget) copy(ctx context.Context, sourceClient *s3.Client, sourceBucket, sourceKey, targetKey string) error {
+	if t.serverSide {
+		return copyR2ObjectServerSide(ctx, t.client, sourceBucket, sourceKey, t.bucketName, targetKey)
+	}
+	return copyR2ObjectStream(ctx, sourceClient, t.client, sourceBucket, sourceKey, t.bucketName, targetKey)
+}
+
+// runR2Job 按 key 顺序分页处理目录下的对象，每页处理完后记录进度和最后一个 key
+func runR2Job(ctx context.Context, job *model.Job) error {
+	client, account, err := createR2ClientFor(job.Environment, job.CountryCode)
+	if err != nil {
+		return err
+	}
+	target, err := newR2JobTarget(job, client, account)
+	if err != nil {
+		return err
+	}
+
+	// 首次执行时统计对象总数，恢复执行时沿用已有统计
+	if job.LastKey == "" && job.DoneObjects == 0 {
+		if job.TotalObjects, job.TotalBytes, err = countR2Objects(ctx, client, job.BucketName, job.SourceDir); err != nil {
+			return err
+		}
+		saveJobProgress(job)
+	}
+
+	// 增量同步每次执行时重新列出目标目录，恢复执行时已复制的对象会被视为未变化
+	var filter *r2SyncFilter
+	if job.Type == model.JobTypeR2Copy && job.Sync {
+		if filter, err = newR2SyncFilter(ctx, target.client, target.bucketName, job.TargetDir, job.SourceDir); err != nil {
+			return err
+		}
+	}
+	resumed := job.LastKey != ""
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(job.BucketName),
+		Prefix: aws.String(job.SourceDir),
+	}
+	if resumed {
+		input.StartAfter = aws.String(job.LastKey)
+	}
+
+	paginator := s3.NewListObjectsV2Paginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %v", err)
+		}
+		if len(page.Contents) == 0 {
+			continue
+		}
+
+		processR2JobPage(ctx, client, target, job, page.Contents, filter)
+		if ctx.Err() != nil {
+			// 本页未处理完，不推进 LastKey
+			saveJobProgress(job)
+			return ctx.Err()
+		}
+
+		job.LastKey = aws.ToString(page.Contents[len(page.Contents)-1].Key)
+		saveJobProgress(job)
+	}
+
+	if filter != nil && job.DeleteExtra {
+		if err := trashR2JobExtras(ctx, client, target, job, filter, resumed); err != nil {
+			return err
+		}
+	}
+
+	// 复制和发布任务可以要求校验
+	if job.Verify && job.FailedObjects == 0 {
+		return verifyR2Job(ctx, client, target, job)
+	}
+	return nil
+}
+
+// verifyR2Job 校验复制任务的目标目录与源目录一致，不一致的对象记入任务错误
+func verifyR2Job(ctx context.Context, client *s3.Client, target *r2JobTarget, job *model.Job) error {
+	source := R2Location{Env: job.Environment, CountryCode: job.CountryCode, BucketName: job.BucketName, Prefix: job.SourceDir}
+	targetLocation := R2Location{Env: job.Environment, CountryCode: job.CountryCode, BucketName: target.bucketName, Prefix: job.TargetDir}
+	if job.TargetEnv != "" {
+		targetLocation.Env, targetLocation.CountryCode = job.TargetEnv, job.TargetCountry
+	}
+
+	result, err := verifyR2Prefixes(ctx, client, target.client, source, targetLocation)
+	if err != nil {
+		return fmt.Errorf("failed to verify copy: %v", err)
+	}
+	for _, mismatch := range result.Mismatches {
+		if len(job.Errors) >= model.JobMaxErrors {
+			break
+		}
+		job.Errors = append(job.Errors, model.JobError{Key: job.TargetDir + mismatch.Key, Error: "verify: " + mismatch.Type})
+	}
+	return result.err()
+}
+
+// trashR2JobExtras 将目标目录中源目录没有的对象移入目标桶的回收站，可以通过 RestoreR2Trash 恢复
+// 只在源目录已完整列出后调用；目标为整个桶、源目录为空或有源对象被跳过时不处理
+// 恢复执行时之前的源对象未经过 filter，需要重新列出源目录
+func trashR2JobExtras(ctx context.Context, client *s3.Client, target *r2JobTarget, job *model.Job, filter *r2SyncFilter, resumed bool) error {
+	if job.TargetDir == "" {
+		return fmt.Errorf("%w: target prefix is empty", ErrR2DeleteExtraUnsafe)
+	}
+	if job.SkippedObjects > 0 {
+		return fmt.Errorf("refusing to delete extra objects: %d source objects were skipped", job.SkippedObjects)
+	}
+
+	if resumed {
+		source, err := listR2ObjectIndex(ctx, client, job.BucketName, job.SourceDir)
+		if err != nil {
+			return err
+		}
+		for relative := range source {
+			filter.seen[relative] = true
+		}
+	}
+
+	if len(filter.seen) == 0 {
+		return fmt.Errorf("%w: source %s is empty", ErrR2DeleteExtraUnsafe, job.SourceDir)
+	}
+
+	extras := filter.extras(job.TargetDir)
+	if len(extras) == 0 {
+		return nil
+	}
+	entry, err := newR2TrashEntry(ctx, target.client, target.bucketName, strings.TrimSuffix(job.TargetDir, "/"))
+	if err != nil {
+		return err
+	}
+	job.TrashID = entry.ID
+	saveJobProgress(job)
+	logger.Info("Moving %d extra objects of %s in bucket %s to trash entry %s", len(extras), job.TargetDir, target.bucketName, entry.ID)
+
+	items := make(chan r2BulkItem, len(extras))
+	for _, item := range extras {
+		items <- item
+	}
+	close(items)
+
+	summary := moveR2Objects(ctx, target.client, target.bucketName, job.TargetDir, entry.prefix(), items, R2BulkOptions{Concurrency: job.Concurrency})
+	job.DeletedObjects += int64(summary.Succeeded)
+	for _, result := range summary.Results {
+		if result.Status == R2KeyFailed {
+			recordJobError(job, result.Key, result.Error)
+		}
+	}
+	saveJobProgress(job)
+	return ctx.Err()
+}
+
+// processR2JobPage 使用批量引擎处理一页对象并累计到任务进度，任务取消后剩余对象不计入
+// filter 不为空时跳过目标中未变化的对象
+func processR2JobPage(ctx context.Context, client *s3.Client, target *r2JobTarget, job *model.Job, objects []types.Object, filter *r2SyncFilter) {
+	candidates := make([]r2BulkItem, 0, len(objects))
+	for _, object := range objects {
+		candidates = append(candidates, newR2BulkItem(object))
+	}
+	if filter != nil {
+		candidates = filter.changedItems(ctx, client, target, job, candidates)
+	}
+
+	items := make(chan r2BulkItem, len(candidates))
+	for _, item := range candidates {
+		items <- item
+	}
+	close(items)
+
+	opts := R2BulkOptions{Concurrency: job.Concurrency}
+	var summary *R2BulkSummary
+	switch job.Type {
+	case model.JobTypeR2Delete:
+		// 一页最多 1000 个对象，正好是一次 DeleteObjects 请求
+		summary = runR2BatchDelete(ctx, client, job.BucketName, items, opts)
+	case model.JobTypeR2Move:
+		summary = moveR2Objects(ctx, client, job.BucketName, job.SourceDir, job.TargetDir, items, opts)
+	default:
+		summary = runR2Bulk(ctx, items, opts, func(ctx context.Context, item r2BulkItem) error {
+			targetKey := job.TargetDir + strings.TrimPrefix(item.Key, job.SourceDir)
+			return target.copy(ctx, client, job.BucketName, item.Key, targetKey)
+		})
+	}
+
+	job.DoneObjects += int64(summary.Succeeded)
+	job.DoneBytes += summary.Bytes
+	for _, result := range summary.Results {
+		switch {
+		case result.Status == R2KeyFailed:
+			recordJobError(job, result.Key, result.Error)
+		case result.Status == R2KeySkipped && ctx.Err() == nil:
+			job.SkippedObjects++
+		}
+	}
+}
+
+// moveR2Objects 在同一桶内将对象从 sourcePrefix 复制到 targetPrefix 后批量删除复制成功的源对象
+// 只有复制和删除都成功的对象计为成功；中断时源对象保留，重新执行会再次复制
+func moveR2Objects(ctx context.Context, client *s3.Client, bucketName, sourcePrefix, targetPrefix string, items <-chan r2BulkItem, opts R2BulkOptions) *R2BulkSummary {
+	var (
+		mu    sync.Mutex
+		sizes = make(map[string]int64)
+	)
+	copied := runR2Bulk(ctx, items, opts, func(ctx context.Context, item r2BulkItem) error {
+		mu.Lock()
+		sizes[item.Key] = item.Size
+		mu.Unlock()
+
+		targetKey := targetPrefix + strings.TrimPrefix(item.Key, sourcePrefix)
+		return copyR2ObjectServerSide(ctx, client, bucketName, item.Key, bucketName, targetKey)
+	})
+
+	summary := &R2BulkSummary{Results: make([]R2KeyResult, 0)}
+	moved := make(chan r2BulkItem, len(copied.Results))
+	for _, result := range copied.Results {
+		if result.Status != R2KeySucceeded {
+			summary.add(result, 0)
+			continue
+		}
+		moved <- r2BulkItem{Key: result.Key}
+	}
+	close(moved)
+
+	deleted := runR2BatchDelete(ctx, client, bucketName, moved, opts)
+	for _, result := range deleted.Results {
+		summary.add(result, sizes[result.Key])
+	}
+	summary.sortResults()
+	return summary
+}
+
+// countR2Objects 统计前缀下的对象数量和总大小
+func countR2Objects(ctx context.Context, client *s3.Client, bucketName, prefix string) (int64, int64, error) {
+	var count, size int64
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to list objects: %v", err)
+		}
+		for _, object := range page.Contents {
+			count++
+			size += aws.ToInt64(object.Size)
+		}
+	}
+	return count, size, nil
+}
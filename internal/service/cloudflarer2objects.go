@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// R2ListOptions R2 对象列表查询参数
+type R2ListOptions struct {
+	Prefix            string
+	Delimiter         string // 为空时不按目录归并，返回前缀下的所有对象
+	ContinuationToken string // 上一页返回的 NextContinuationToken
+	PageSize          int32  // 单页数量，范围 1-1000，0 表示使用默认值 1000
+	VersionsOnly      bool   // 只返回版本目录（latest 或以 v 开头），不返回对象
+}
+
+// ListR2Objects 分页列出 R2 对象及目录
+func ListR2Objects(countryCode, env, bucketName string, opts R2ListOptions) (*constants.R2ListResult, error) {
+	if opts.PageSize < 0 || opts.PageSize > constants.R2ListMaxKeys {
+		return nil, fmt.Errorf("page size must be between 1 and %d", constants.R2ListMaxKeys)
+	}
+	if opts.VersionsOnly && opts.Delimiter == "" {
+		opts.Delimiter = "/"
+	}
+
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+	}
+	if opts.Prefix != "" {
+		input.Prefix = aws.String(opts.Prefix)
+	}
+	if opts.Delimiter != "" {
+		input.Delimiter = aws.String(opts.Delimiter)
+	}
+	if opts.ContinuationToken != "" {
+		input.ContinuationToken = aws.String(opts.ContinuationToken)
+	}
+	if opts.PageSize > 0 {
+		input.MaxKeys = aws.Int32(opts.PageSize)
+	}
+
+	logger.Info("Listing R2 objects in bucket %s, prefix: %s, delimiter: %s, versions only: %t",
+		bucketName, opts.Prefix, opts.Delimiter, opts.VersionsOnly)
+
+	output, err := client.ListObjectsV2(context.TODO(), input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list objects: %v", err)
+	}
+
+	result := &constants.R2ListResult{
+		Bucket:      bucketName,
+		Prefix:      opts.Prefix,
+		Delimiter:   opts.Delimiter,
+		Folders:     make([]constants.R2Folder, 0, len(output.CommonPrefixes)),
+		Objects:     make([]constants.R2Object, 0, len(output.Contents)),
+		KeyCount:    aws.ToInt32(output.KeyCount),
+		IsTruncated: aws.ToBool(output.IsTruncated),
+	}
+	if result.IsTruncated {
+		result.NextContinuationToken = aws.ToString(output.NextContinuationToken)
+	}
+
+	for _, commonPrefix := range output.CommonPrefixes {
+		prefix := aws.ToString(commonPrefix.Prefix)
+		name := strings.TrimSuffix(strings.TrimPrefix(prefix, opts.Prefix), opts.Delimiter)
+		if opts.VersionsOnly && !isVersionDirectory(name) {
+			continue
+		}
+		result.Folders = append(result.Folders, constants.R2Folder{
+			Prefix: prefix,
+			Name:   name,
+		})
+	}
+
+	if !opts.VersionsOnly {
+		for _, object := range output.Contents {
+			result.Objects = append(result.Objects, constants.R2Object{
+				Key:          aws.ToString(object.Key),
+				Size:         aws.ToInt64(object.Size),
+				ETag:         strings.Trim(aws.ToString(object.ETag), `"`),
+				LastModified: aws.ToTime(object.LastModified),
+				StorageClass: string(object.StorageClass),
+			})
+		}
+	}
+
+	return result, nil
+}
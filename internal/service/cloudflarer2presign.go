@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"openapi/internal/config"
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// DefaultR2PresignExpiry 未配置 r2_presign_default_expiry 时预签名 URL 的有效期
+	DefaultR2PresignExpiry = 15 * time.Minute
+
+	// DefaultR2PresignMaxExpiry 未配置 r2_presign_max_expiry 时预签名 URL 的最长有效期
+	DefaultR2PresignMaxExpiry = time.Hour
+)
+
+// ErrR2PresignExpiry 有效期超出策略允许的范围
+var ErrR2PresignExpiry = errors.New("presign expiry not allowed by policy")
+
+// R2PresignPolicy 返回预签名 URL 的默认有效期和最长有效期
+func R2PresignPolicy() (defaultExpiry, maxExpiry time.Duration) {
+	defaultExpiry = DefaultR2PresignExpiry
+	maxExpiry = DefaultR2PresignMaxExpiry
+	if seconds := config.GlobalConfig.Cloudflare.R2PresignMaxExpiry; seconds > 0 {
+		maxExpiry = time.Duration(seconds) * time.Second
+	}
+	if seconds := config.GlobalConfig.Cloudflare.R2PresignDefaultExpiry; seconds > 0 {
+		defaultExpiry = time.Duration(seconds) * time.Second
+	}
+	if defaultExpiry > maxExpiry {
+		defaultExpiry = maxExpiry
+	}
+	return defaultExpiry, maxExpiry
+}
+
+// PresignR2Object 生成对象的预签名 GET 或 PUT URL，expiry 为 0 时使用默认有效期
+// PUT 指定 contentType 时该请求头会参与签名，上传时必须携带相同的 Content-Type
+func PresignR2Object(countryCode, env, bucketName, key, method, contentType string, expiry time.Duration) (*constants.R2PresignedURL, error) {
+	defaultExpiry, maxExpiry := R2PresignPolicy()
+	if expiry == 0 {
+		expiry = defaultExpiry
+	}
+	if expiry < time.Second || expiry > maxExpiry {
+		return nil, fmt.Errorf("%w: expiry must be between 1s and %s", ErrR2PresignExpiry, maxExpiry)
+	}
+
+	// 使用请求环境的账号签名，URL 只能访问该账号下的桶
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+	presignClient := s3.NewPresignClient(client)
+	withExpiry := s3.WithPresignExpires(expiry)
+
+	var request *v4.PresignedHTTPRequest
+	switch method {
+	case http.MethodGet:
+		request, err = presignClient.PresignGetObject(context.TODO(), &s3.GetObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(key),
+		}, withExpiry)
+	case http.MethodPut:
+		input := &s3.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(key),
+		}
+		if contentType != "" {
+			input.ContentType = aws.String(contentType)
+		}
+		request, err = presignClient.PresignPutObject(context.TODO(), input, withExpiry)
+	default:
+		return nil, fmt.Errorf("unsupported presign method: %s", method)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign %s %s: %v", method, key, err)
+	}
+
+	// Host 由客户端自动设置，不需要调用方处理
+	headers := make(map[string][]string)
+	for name, values := range request.SignedHeader {
+		if http.CanonicalHeaderKey(name) != "Host" {
+			headers[name] = values
+		}
+	}
+
+	logger.Info("Presigned %s URL for %s in bucket %s, expires in %s", method, key, bucketName, expiry)
+
+	return &constants.R2PresignedURL{
+		Bucket:    bucketName,
+		Key:       key,
+		Method:    request.Method,
+		URL:       request.URL,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expiry).UTC(),
+	}, nil
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// 版本目录保留或删除的原因
+const (
+	R2RetentionLatest     = "latest"     // latest 目录
+	R2RetentionNewest     = "newest"     // 最新的 N 个版本之一
+	R2RetentionReferenced = "referenced" // 被 KV 版本 key 引用
+	R2RetentionReleasing  = "releasing"  // 正在发布
+	R2RetentionNotSemver  = "not_semver" // 不是 vX.Y.Z 格式，不自动删除
+	R2RetentionExpired    = "expired"    // 超出保留数量且未被引用
+)
+
+const (
+	// r2RetentionCheckInterval 定时执行保留策略的检查间隔
+	r2RetentionCheckInterval = 10 * time.Minute
+
+	// r2RetentionOperator 定时执行时记录的操作人
+	r2RetentionOperator = "retention-scheduler"
+)
+
+// ErrR2RetentionPolicyNotFound bucket 未配置保留策略
+var ErrR2RetentionPolicyNotFound = errors.New("retention policy not found")
+
+// R2RetentionDirectory 保留计划中的版本目录
+type R2RetentionDirectory struct {
+	Name    string `json:"name"`
+	Reason  string `json:"reason"`
+	Objects int64  `json:"objects,omitempty"` // 仅预览时统计将删除目录的对象数量
+	Bytes   int64  `json:"bytes,omitempty"`
+}
+
+// R2VersionReference 引用版本目录的 KV key
+type R2VersionReference struct {
+	NamespaceID    string `json:"namespace_id"`
+	NamespaceTitle string `json:"namespace_title"`
+	KeyName        string `json:"key_name"`
+	Version        string `json:"version"`
+}
+
+// R2RetentionPlan 保留策略的执行计划
+type R2RetentionPlan struct {
+	Bucket     string                 `json:"bucket"`
+	KeepCount  int                    `json:"keep_count"`
+	Keep       []R2RetentionDirectory `json:"keep"`
+	Delete     []R2RetentionDirectory `json:"delete"`
+	References []R2VersionReference   `json:"references"`
+}
+
+// R2RetentionResult 执行保留策略的结果
+type R2RetentionResult struct {
+	Plan *R2RetentionPlan `json:"plan"`
+	Jobs []model.Job      `json:"jobs"` // 为每个要删除的目录创建的移入回收站任务，已有未结束任务的目录沿用原任务
+}
+
+// SaveR2RetentionPolicy 创建或更新 bucket 的保留策略
+func SaveR2RetentionPolicy(countryCode, env, bucketName string, keepCount, intervalHours int, operator string) (*model.R2RetentionPolicy, error) {
+	if keepCount < 1 {
+		return nil, fmt.Errorf("keep count must be at least 1")
+	}
+
+	policy, err := GetR2RetentionPolicy(countryCode, env, bucketName)
+	if err != nil && !errors.Is(err, ErrR2RetentionPolicyNotFound) {
+		return nil, err
+	}
+	if policy == nil {
+		policy = &model.R2RetentionPolicy{
+			Environment: env,
+			CountryCode: countryCode,
+			BucketName:  bucketName,
+		}
+	}
+	policy.KeepCount = keepCount
+	policy.IntervalHours = intervalHours
+	policy.Operator = operator
+
+	if err := db.DB.Save(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to save retention policy: %v", err)
+	}
+
+	logger.Info("Retention policy for %s saved by %s: keep %d, every %d hours", bucketName, operator, keepCount, intervalHours)
+	return policy, nil
+}
+
+// GetR2RetentionPolicy 获取 bucket 的保留策略
+func GetR2RetentionPolicy(countryCode, env, bucketName string) (*model.R2RetentionPolicy, error) {
+	var policy model.R2RetentionPolicy
+	err := db.DB.Where("environment = ? AND country_code = ? AND bucket_name = ?", env, countryCode, bucketName).
+		First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %s", ErrR2RetentionPolicyNotFound, bucketName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get retention policy: %v", err)
+	}
+	return &policy, nil
+}
+
+// ListR2RetentionPolicies 获取环境和国家下的所有保留策略
+func ListR2RetentionPolicies(countryCode, env string) ([]model.R2RetentionPolicy, error) {
+	var policies []model.R2RetentionPolicy
+	err := db.DB.Where("environment = ? AND country_code = ?", env, countryCode).
+		Order("bucket_name").Find(&policies).Error
+	return policies, err
+}
+
+// DeleteR2RetentionPolicy 删除 bucket 的保留策略，不影响已创建的删除任务
+func DeleteR2RetentionPolicy(countryCode, env, bucketName, operator string) error {
+	policy, err := GetR2RetentionPolicy(countryCode, env, bucketName)
+	if err != nil {
+		return err
+	}
+	if err := db.DB.Unscoped().Delete(policy).Error; err != nil {
+		return fmt.Errorf("failed to delete retention policy: %v", err)
+	}
+
+	logger.Info("Retention policy for %s deleted by %s", bucketName, operator)
+	return nil
+}
+
+// PreviewR2Retention 计算保留策略将删除的版本目录及其对象数量和总大小，不做任何修改
+// keepCount 为 0 时使用 bucket 已配置的策略
+func PreviewR2Retention(countryCode, env, bucketName string, keepCount int) (*R2RetentionPlan, error) {
+	plan, err := planR2Retention(countryCode, env, bucketName, keepCount)
+	if err != nil {
+		return nil, err
+	}
+
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+	for i := range plan.Delete {
+		dir := &plan.Delete[i]
+		if dir.Objects, dir.Bytes, err = countR2Objects(context.TODO(), client, bucketName, dir.Name+"/"); err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// ExecuteR2Retention 将保留策略计划删除的每个版本目录移入回收站，超过回收站保留时间后才会被永久删除
+// keepCount 为 0 时使用 bucket 已配置的策略
+func ExecuteR2Retention(countryCode, env, bucketName string, keepCount int, operator string) (*R2RetentionResult, error) {
+	plan, err := planR2Retention(countryCode, env, bucketName, keepCount)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &R2RetentionResult{Plan: plan, Jobs: make([]model.Job, 0, len(plan.Delete))}
+	for _, dir := range plan.Delete {
+		job, err := findUnfinishedR2Job(countryCode, env, bucketName, model.JobTypeR2Move, dir.Name+"/")
+		if err != nil {
+			return result, err
+		}
+		if job == nil {
+			job, err = TrashDirectory(countryCode, env, bucketName, dir.Name, operator, R2BulkOptions{})
+			if errors.Is(err, ErrR2DirectoryEmpty) {
+				// 列出目录后已被其他操作删除或移走
+				logger.Info("Skipping %s of bucket %s: %v", dir.Name, bucketName, err)
+				continue
+			}
+			if err != nil {
+				return result, err
+			}
+		}
+		result.Jobs = append(result.Jobs, *job)
+	}
+
+	logger.Info("Retention of %s by %s: keeping %d directories, deleting %d",
+		bucketName, operator, len(plan.Keep), len(plan.Delete))
+	return result, nil
+}
+
+// planR2Retention 列出 bucket 中的版本目录，按版本号从新到旧保留 keepCount 个，
+// 并始终保留 latest、被 KV 版本 key 引用和正在发布的版本
+// 无法读取 KV 引用时返回错误，不会生成删除计划
+func planR2Retention(countryCode, env, bucketName string, keepCount int) (*R2RetentionPlan, error) {
+	if keepCount <= 0 {
+		policy, err := GetR2RetentionPolicy(countryCode, env, bucketName)
+		if err != nil {
+			return nil, err
+		}
+		keepCount = policy.KeepCount
+	}
+
+	dirs, err := listR2VersionDirectories(countryCode, env, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	references, err := findR2VersionReferences(countryCode, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read version references: %v", err)
+	}
+
+	releasing, err := findReleasingVersions(countryCode, env, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildR2RetentionPlan(bucketName, keepCount, dirs, references, releasing), nil
+}
+
+// buildR2RetentionPlan 根据版本目录、KV 引用和正在发布的版本生成保留计划
+// 版本号最新的 keepCount 个目录、latest、不是 vX.Y.Z 格式的目录以及被引用或正在发布的版本都会保留
+func buildR2RetentionPlan(bucketName string, keepCount int, dirs []string, references []R2VersionReference, releasing map[string]bool) *R2RetentionPlan {
+	referenced := make(map[string]bool, len(references))
+	for _, ref := range references {
+		referenced[ref.Version] = true
+	}
+
+	plan := &R2RetentionPlan{
+		Bucket:     bucketName,
+		KeepCount:  keepCount,
+		Keep:       make([]R2RetentionDirectory, 0),
+		Delete:     make([]R2RetentionDirectory, 0),
+		References: references,
+	}
+
+	versions := make([]string, 0, len(dirs))
+	for _, name := range dirs {
+		switch {
+		case name == "latest":
+			plan.Keep = append(plan.Keep, R2RetentionDirectory{Name: name, Reason: R2RetentionLatest})
+		case releaseVersionPattern.MatchString(name):
+			versions = append(versions, name)
+		default:
+			plan.Keep = append(plan.Keep, R2RetentionDirectory{Name: name, Reason: R2RetentionNotSemver})
+		}
+	}
+
+	// 按版本号从新到旧排序
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareSemver(versions[i], versions[j]) > 0
+	})
+	for i, name := range versions {
+		switch {
+		case i < keepCount:
+			plan.Keep = append(plan.Keep, R2RetentionDirectory{Name: name, Reason: R2RetentionNewest})
+		case referenced[name]:
+			plan.Keep = append(plan.Keep, R2RetentionDirectory{Name: name, Reason: R2RetentionReferenced})
+		case releasing[name]:
+			plan.Keep = append(plan.Keep, R2RetentionDirectory{Name: name, Reason: R2RetentionReleasing})
+		default:
+			plan.Delete = append(plan.Delete, R2RetentionDirectory{Name: name, Reason: R2RetentionExpired})
+		}
+	}
+
+	return plan
+}
+
+// listR2VersionDirectories 列出 bucket 根目录下的所有版本目录（latest 或以 v 开头）
+func listR2VersionDirectories(countryCode, env, bucketName string) ([]string, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := listR2CommonPrefixes(context.TODO(), client, bucketName, "")
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := make([]string, 0, len(names))
+	for _, name := range names {
+		if isVersionDirectory(name) {
+			dirs = append(dirs, name)
+		}
+	}
+	return dirs, nil
+}
+
+// findR2VersionReferences 读取账号下所有命名空间（逐页读取）中匹配 kv_key_patterns 的 key 当前引用的版本，任何读取失败都返回错误
+func findR2VersionReferences(countryCode, env string) ([]R2VersionReference, error) {
+	patterns, err := compileKeyPatterns(KVKeyPatterns())
+	if err != nil {
+		return nil, err
+	}
+
+	// 命名空间列表不完整时无法确定哪些版本仍被引用，直接失败
+	namespaces, err := GetKVNamespaces(countryCode, env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KV namespaces: %v", err)
+	}
+
+	references := make([]R2VersionReference, 0)
+	for _, namespace := range namespaces.Result {
+		keys, err := GetKVKeys(countryCode, env, namespace.ID, KVKeysListOptions{All: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys.Result {
+			if !matchesAnyPattern(patterns, key.Name) {
+				continue
+			}
+			value, exists, err := readKVValueIfExists(KVLocation{
+				Env:         env,
+				CountryCode: countryCode,
+				NamespaceID: namespace.ID,
+			}, key.Name)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+			references = append(references, R2VersionReference{
+				NamespaceID:    namespace.ID,
+				NamespaceTitle: namespace.Title,
+				KeyName:        key.Name,
+				Version:        strings.Trim(strings.TrimSpace(value), "/"),
+			})
+		}
+	}
+	return references, nil
+}
+
+// findReleasingVersions 返回 bucket 中正在发布的版本
+func findReleasingVersions(countryCode, env, bucketName string) (map[string]bool, error) {
+	var versions []string
+	if err := db.DB.Model(&model.ReleaseRecord{}).
+		Where("environment = ? AND country_code = ? AND bucket_name = ? AND active_key IS NOT NULL",
+			env, countryCode, bucketName).
+		Pluck("version", &versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to check running releases: %v", err)
+	}
+
+	releasing := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		releasing[version] = true
+	}
+	return releasing, nil
+}
+
+// findUnfinishedR2Job 查找以目录为源的未结束的指定类型任务，不存在时返回 nil
+func findUnfinishedR2Job(countryCode, env, bucketName, jobType, dirPath string) (*model.Job, error) {
+	var jobs []model.Job
+	if err := db.DB.Where("type = ? AND environment = ? AND country_code = ? AND bucket_name = ? AND source_dir = ? AND status IN ?",
+		jobType, env, countryCode, bucketName, dirPath,
+		[]string{model.JobStatusPending, model.JobStatusRunning}).
+		Limit(1).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to check running jobs: %v", err)
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// compareSemver 比较两个 vX.Y.Z[-pre][+build] 版本号，a 较新时返回正数
+func compareSemver(a, b string) int {
+	aCore, aPre := splitSemver(a)
+	bCore, bPre := splitSemver(b)
+
+	for i := 0; i < 3; i++ {
+		if aCore[i] != bCore[i] {
+			if aCore[i] > bCore[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	// 没有预发布标识的版本比有预发布标识的版本新
+	switch {
+	case aPre == "" && bPre == "":
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aIDs, bIDs := strings.Split(aPre, "."), strings.Split(bPre, ".")
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		if c := comparePrereleaseID(aIDs[i], bIDs[i]); c != 0 {
+			return c
+		}
+	}
+	return len(aIDs) - len(bIDs)
+}
+
+// splitSemver 拆分版本号的主次修订号和预发布标识，忽略构建元数据
+func splitSemver(version string) ([3]uint64, string) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+
+	var pre string
+	if i := strings.Index(version, "-"); i >= 0 {
+		version, pre = version[:i], version[i+1:]
+	}
+
+	var core [3]uint64
+	for i, part := range strings.SplitN(version, ".", 3) {
+		core[i], _ = strconv.ParseUint(part, 10, 64)
+	}
+	return core, pre
+}
+
+// comparePrereleaseID 比较单个预发布标识，数字标识按数值比较且低于字母标识
+func comparePrereleaseID(a, b string) int {
+	aNum, aErr := strconv.ParseUint(a, 10, 64)
+	bNum, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		if aNum == bNum {
+			return 0
+		}
+		if aNum > bNum {
+			return 1
+		}
+		return -1
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// StartR2RetentionScheduler 在后台定时执行配置了执行间隔的保留策略
+func StartR2RetentionScheduler() {
+	go func() {
+		ticker := time.NewTicker(r2RetentionCheckInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			runDueR2RetentionPolicies()
+		}
+	}()
+}
+
+// runDueR2RetentionPolicies 执行所有已到执行时间的保留策略，并记录执行时间和错误
+func runDueR2RetentionPolicies() {
+	var policies []model.R2RetentionPolicy
+	if err := db.DB.Where("interval_hours > 0").Find(&policies).Error; err != nil {
+		logger.Error("Failed to load retention policies: %v", err)
+		return
+	}
+
+	for _, policy := range policies {
+		if policy.LastRunAt != nil && time.Since(*policy.LastRunAt) < time.Duration(policy.IntervalHours)*time.Hour {
+			continue
+		}
+
+		now := time.Now()
+		lastError := ""
+		if _, err := ExecuteR2Retention(policy.CountryCode, policy.Environment, policy.BucketName, policy.KeepCount, r2RetentionOperator); err != nil {
+			logger.Error("Scheduled retention of %s failed: %v", policy.BucketName, err)
+			lastError = err.Error()
+		}
+
+		if err := db.DB.Model(&policy).Updates(map[string]interface{}{"last_run_at": now, "last_error": lastError}).Error; err != nil {
+			logger.Error("Failed to update retention policy %d: %v", policy.ID, err)
+		}
+	}
+}
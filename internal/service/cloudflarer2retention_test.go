@@ -0,0 +1,153 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "v1.2.3", 0},
+		{"v1.2.4", "v1.2.3", 1},
+		{"v1.10.0", "v1.9.9", 1},
+		{"v2.0.0", "v10.0.0", -1},
+		{"v1.0.0", "v1.0.0-rc.1", 1},
+		{"v1.0.0-alpha", "v1.0.0", -1},
+		{"v1.0.0-alpha", "v1.0.0-alpha.1", -1},
+		{"v1.0.0-alpha.1", "v1.0.0-alpha.beta", -1},
+		{"v1.0.0-beta", "v1.0.0-alpha.beta", 1},
+		{"v1.0.0-beta.11", "v1.0.0-beta.2", 1},
+		{"v1.0.0-rc.1", "v1.0.0-beta.11", 1},
+		{"v1.0.0+build.2", "v1.0.0+build.1", 0},
+		{"v1.0.0-rc.1+build.5", "v1.0.0-rc.1", 0},
+		{"v1.0.1+build.1", "v1.0.0+build.9", 1},
+	}
+	for _, tt := range tests {
+		if got := sign(compareSemver(tt.a, tt.b)); got != tt.want {
+			t.Errorf("compareSemver(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := sign(compareSemver(tt.b, tt.a)); got != -tt.want {
+			t.Errorf("compareSemver(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestBuildR2RetentionPlan(t *testing.T) {
+	tests := []struct {
+		name       string
+		keepCount  int
+		dirs       []string
+		references []string
+		releasing  []string
+		keep       map[string]string
+		delete     []string
+	}{
+		{
+			name:      "keeps newest versions",
+			keepCount: 2,
+			dirs:      []string{"v1.0.0", "v1.2.0", "v1.10.0", "v1.9.0"},
+			keep:      map[string]string{"v1.10.0": R2RetentionNewest, "v1.9.0": R2RetentionNewest},
+			delete:    []string{"v1.2.0", "v1.0.0"},
+		},
+		{
+			name:      "release is newer than its pre-releases",
+			keepCount: 1,
+			dirs:      []string{"v2.0.0-rc.2", "v2.0.0", "v2.0.0-rc.10", "v1.9.0"},
+			keep:      map[string]string{"v2.0.0": R2RetentionNewest},
+			delete:    []string{"v2.0.0-rc.10", "v2.0.0-rc.2", "v1.9.0"},
+		},
+		{
+			name:      "build metadata does not affect ordering",
+			keepCount: 1,
+			dirs:      []string{"v1.0.0+build.9", "v1.0.1+build.1"},
+			keep:      map[string]string{"v1.0.1+build.1": R2RetentionNewest},
+			delete:    []string{"v1.0.0+build.9"},
+		},
+		{
+			name:      "latest and non-semver directories are never deleted",
+			keepCount: 1,
+			dirs:      []string{"latest", "assets", "v1", "v1.0.0", "v0.9.0"},
+			keep: map[string]string{
+				"latest": R2RetentionLatest,
+				"assets": R2RetentionNotSemver,
+				"v1":     R2RetentionNotSemver,
+				"v1.0.0": R2RetentionNewest,
+			},
+			delete: []string{"v0.9.0"},
+		},
+		{
+			name:       "referenced and releasing versions are kept",
+			keepCount:  1,
+			dirs:       []string{"v1.0.0", "v1.1.0", "v1.2.0", "v1.3.0"},
+			references: []string{"v1.0.0"},
+			releasing:  []string{"v1.1.0"},
+			keep: map[string]string{
+				"v1.3.0": R2RetentionNewest,
+				"v1.1.0": R2RetentionReleasing,
+				"v1.0.0": R2RetentionReferenced,
+			},
+			delete: []string{"v1.2.0"},
+		},
+		{
+			name:       "newest takes precedence over referenced and referenced over releasing",
+			keepCount:  1,
+			dirs:       []string{"v1.0.0", "v2.0.0"},
+			references: []string{"v2.0.0", "v1.0.0"},
+			releasing:  []string{"v2.0.0", "v1.0.0"},
+			keep:       map[string]string{"v2.0.0": R2RetentionNewest, "v1.0.0": R2RetentionReferenced},
+		},
+		{
+			name:       "references to missing directories are ignored",
+			keepCount:  1,
+			dirs:       []string{"v1.0.0", "v2.0.0"},
+			references: []string{"v3.0.0"},
+			keep:       map[string]string{"v2.0.0": R2RetentionNewest},
+			delete:     []string{"v1.0.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			references := make([]R2VersionReference, 0, len(tt.references))
+			for _, version := range tt.references {
+				references = append(references, R2VersionReference{KeyName: "version", Version: version})
+			}
+			releasing := make(map[string]bool, len(tt.releasing))
+			for _, version := range tt.releasing {
+				releasing[version] = true
+			}
+
+			plan := buildR2RetentionPlan("assets", tt.keepCount, tt.dirs, references, releasing)
+
+			keep := make(map[string]string, len(plan.Keep))
+			for _, dir := range plan.Keep {
+				keep[dir.Name] = dir.Reason
+			}
+			if !reflect.DeepEqual(keep, tt.keep) {
+				t.Errorf("keep = %v, want %v", keep, tt.keep)
+			}
+			var deleted []string
+			for _, dir := range plan.Delete {
+				if dir.Reason != R2RetentionExpired {
+					t.Errorf("delete %s reason = %s, want %s", dir.Name, dir.Reason, R2RetentionExpired)
+				}
+				deleted = append(deleted, dir.Name)
+			}
+			if !reflect.DeepEqual(deleted, tt.delete) {
+				t.Errorf("delete = %v, want %v", deleted, tt.delete)
+			}
+		})
+	}
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// r2SyncFilter 增量同步时对比源和目标对象，key 均为相对各自前缀的路径
+type r2SyncFilter struct {
+	sourcePrefix string
+	target       map[string]r2BulkItem
+	seen         map[string]bool // 已列出的源对象
+}
+
+// newR2SyncFilter 列出目标前缀下的所有对象用于对比
+func newR2SyncFilter(ctx context.Context, client *s3.Client, bucketName, targetPrefix, sourcePrefix string) (*r2SyncFilter, error) {
+	target, err := listR2ObjectIndex(ctx, client, bucketName, targetPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return &r2SyncFilter{
+		sourcePrefix: sourcePrefix,
+		target:       target,
+		seen:         make(map[string]bool),
+	}, nil
+}
+
+// changedItems 记录源对象并返回目标中不存在或已变化、需要复制的对象，未变化的对象计入任务进度
+// 大小相同但至少一端为分片上传、ETag 无法直接比较时，下载两端内容比较 MD5；比较失败时按已变化处理
+func (f *r2SyncFilter) changedItems(ctx context.Context, sourceClient *s3.Client, target *r2JobTarget, job *model.Job, items []r2BulkItem) []r2BulkItem {
+	changed := make([]r2BulkItem, 0, len(items))
+	pending := make([]r2BulkItem, 0)
+	sourceIndex := make(map[string]r2BulkItem)
+	for _, item := range items {
+		relative := strings.TrimPrefix(item.Key, f.sourcePrefix)
+		f.seen[relative] = true
+
+		existing, ok := f.target[relative]
+		switch {
+		case !ok || existing.Size != item.Size:
+			changed = append(changed, item)
+		case existing.ETag == item.ETag:
+			job.UnchangedObjects++
+			job.DoneObjects++
+		case isMultipartETag(item.ETag) || isMultipartETag(existing.ETag):
+			pending = append(pending, r2BulkItem{Key: relative, Size: item.Size})
+			sourceIndex[relative] = item
+		default:
+			changed = append(changed, item)
+		}
+	}
+	if len(pending) == 0 {
+		return changed
+	}
+
+	mismatches, err := compareR2Checksums(ctx, sourceClient, target.client,
+		R2Location{BucketName: job.BucketName, Prefix: job.SourceDir},
+		R2Location{BucketName: target.bucketName, Prefix: job.TargetDir},
+		pending, sourceIndex, f.target)
+	if err != nil {
+		logger.Error("Failed to compare checksums of %d objects under %s, copying them: %v", len(pending), job.SourceDir, err)
+		for _, item := range pending {
+			changed = append(changed, sourceIndex[item.Key])
+		}
+		return changed
+	}
+
+	differ := make(map[string]bool, len(mismatches))
+	for _, mismatch := range mismatches {
+		differ[mismatch.Key] = true
+	}
+	for _, item := range pending {
+		if differ[item.Key] {
+			changed = append(changed, sourceIndex[item.Key])
+			continue
+		}
+		job.UnchangedObjects++
+		job.DoneObjects++
+	}
+	return changed
+}
+
+// extras 返回目标中有而源中没有的对象，调用前必须已记录所有源对象
+func (f *r2SyncFilter) extras(targetPrefix string) []r2BulkItem {
+	extras := make([]r2BulkItem, 0)
+	for relative, item := range f.target {
+		if !f.seen[relative] {
+			item.Key = targetPrefix + relative
+			extras = append(extras, item)
+		}
+	}
+	return extras
+}
+
+// listR2ObjectIndex 列出前缀下的所有对象，key 为相对前缀的路径
+func listR2ObjectIndex(ctx context.Context, client *s3.Client, bucketName, prefix string) (map[string]r2BulkItem, error) {
+	index := make(map[string]r2BulkItem)
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %v", err)
+		}
+		for _, object := range page.Contents {
+			item := newR2BulkItem(object)
+			index[strings.TrimPrefix(item.Key, prefix)] = item
+		}
+	}
+	return index, nil
+}
+
+// isMultipartETag 判断 ETag 是否来自分片上传（格式为 "<md5>-<分片数>"）
+func isMultipartETag(etag string) bool {
+	return strings.Contains(etag, "-")
+}
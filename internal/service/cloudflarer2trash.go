@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"openapi/internal/config"
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+const (
+	// R2TrashPrefix 回收站前缀，删除的目录移动到 .trash/<删除时间>/<转义后的目录>/ 下
+	R2TrashPrefix = ".trash/"
+
+	// DefaultR2TrashRetentionHours 未配置 r2_trash_retention_hours 时回收站的保留时间（小时）
+	DefaultR2TrashRetentionHours = 168
+
+	// r2TrashTimeFormat 回收站中删除时间的格式（UTC）
+	r2TrashTimeFormat = "20060102T150405Z"
+
+	// r2TrashSuffixBytes 删除时间后随机后缀的字节数，避免同一秒内的删除落到同一前缀
+	r2TrashSuffixBytes = 4
+
+	// r2TrashPurgeInterval 定时清理回收站的间隔
+	r2TrashPurgeInterval = time.Hour
+
+	// r2TrashPurgeOperator 定时清理时记录的操作人
+	r2TrashPurgeOperator = "trash-purger"
+)
+
+var (
+	// ErrR2TrashPath 不能将回收站中的目录再移入回收站
+	ErrR2TrashPath = errors.New("directory is in the trash")
+
+	// ErrR2DirectoryEmpty 目录下没有对象
+	ErrR2DirectoryEmpty = errors.New("directory is empty or does not exist")
+
+	// ErrR2TrashEntryNotFound 回收站中不存在该条目
+	ErrR2TrashEntryNotFound = errors.New("trash entry not found")
+
+	// ErrR2RestoreConflict 恢复的目标目录已存在对象，或条目正在被其他任务处理
+	ErrR2RestoreConflict = errors.New("cannot restore trash entry")
+)
+
+// R2TrashEntry 回收站中的一个目录
+type R2TrashEntry struct {
+	ID        string    `json:"id"`       // 相对 .trash/ 的路径：<删除时间>-<随机后缀>/<转义后的目录>
+	DirPath   string    `json:"dir_path"` // 删除前的目录
+	DeletedAt time.Time `json:"deleted_at"`
+	ExpiresAt time.Time `json:"expires_at"` // 超过该时间后会被清理
+}
+
+// prefix 条目在 bucket 中的前缀
+func (e R2TrashEntry) prefix() string {
+	return R2TrashPrefix + e.ID + "/"
+}
+
+// R2TrashRetention 返回回收站的保留时间
+func R2TrashRetention() time.Duration {
+	hours := config.GlobalConfig.Cloudflare.R2TrashRetentionHours
+	if hours <= 0 {
+		hours = DefaultR2TrashRetentionHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// TrashDirectory 创建将目录移入回收站的后台任务，可以通过 RestoreR2Trash 恢复
+// 目录下没有对象时返回 ErrR2DirectoryEmpty
+func TrashDirectory(countryCode, env, bucketName, dirPath, operator string, opts R2BulkOptions) (*model.Job, error) {
+	dirPath = strings.Trim(dirPath, "/")
+	if dirPath == "" {
+		return nil, fmt.Errorf("directory path is required")
+	}
+	if strings.HasPrefix(dirPath+"/", R2TrashPrefix) {
+		return nil, fmt.Errorf("%w: %s", ErrR2TrashPath, dirPath)
+	}
+
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+	exists, err := r2PrefixHasObjects(context.TODO(), client, bucketName, dirPath+"/")
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrR2DirectoryEmpty, dirPath)
+	}
+
+	entry, err := newR2TrashEntry(context.TODO(), client, bucketName, dirPath)
+	if err != nil {
+		return nil, err
+	}
+	return createJob(&model.Job{
+		Type:        model.JobTypeR2Move,
+		Environment: env,
+		CountryCode: countryCode,
+		BucketName:  bucketName,
+		SourceDir:   dirPath + "/",
+		TargetDir:   entry.prefix(),
+		Concurrency: opts.Concurrency,
+		Operator:    operator,
+	})
+}
+
+// newR2TrashEntry 为目录生成新的回收站条目，条目前缀下已有对象时返回错误
+func newR2TrashEntry(ctx context.Context, client *s3.Client, bucketName, dirPath string) (R2TrashEntry, error) {
+	// 同一秒内多次删除同一目录时，随机后缀保证每次使用不同的前缀，恢复时不会混入其他删除的对象
+	suffix := make([]byte, r2TrashSuffixBytes)
+	if _, err := rand.Read(suffix); err != nil {
+		return R2TrashEntry{}, fmt.Errorf("failed to generate trash entry id: %v", err)
+	}
+	entry := R2TrashEntry{
+		ID:      time.Now().UTC().Format(r2TrashTimeFormat) + "-" + hex.EncodeToString(suffix) + "/" + url.PathEscape(dirPath),
+		DirPath: dirPath,
+	}
+	occupied, err := r2PrefixHasObjects(ctx, client, bucketName, entry.prefix())
+	if err != nil {
+		return R2TrashEntry{}, err
+	}
+	if occupied {
+		return R2TrashEntry{}, fmt.Errorf("trash entry %s already exists", entry.ID)
+	}
+	return entry, nil
+}
+
+// ListR2Trash 列出 bucket 回收站中的目录，按删除时间倒序
+func ListR2Trash(countryCode, env, bucketName string) ([]R2TrashEntry, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamps, err := listR2CommonPrefixes(context.TODO(), client, bucketName, R2TrashPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	retention := R2TrashRetention()
+	entries := make([]R2TrashEntry, 0)
+	for _, timestamp := range timestamps {
+		dirs, err := listR2CommonPrefixes(context.TODO(), client, bucketName, R2TrashPrefix+timestamp+"/")
+		if err != nil {
+			return nil, err
+		}
+		for _, dir := range dirs {
+			entry, err := parseR2TrashID(timestamp + "/" + dir)
+			if err != nil {
+				logger.Info("Skipping unknown trash prefix %s%s/%s/: %v", R2TrashPrefix, timestamp, dir, err)
+				continue
+			}
+			entry.ExpiresAt = entry.DeletedAt.Add(retention)
+			entries = append(entries, *entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// RestoreR2Trash 创建将回收站条目移回原目录的后台任务
+// 原目录已有对象或条目正在被其他任务处理时返回 ErrR2RestoreConflict
+func RestoreR2Trash(countryCode, env, bucketName, id, operator string, opts R2BulkOptions) (*model.Job, error) {
+	entry, err := parseR2TrashID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrR2TrashEntryNotFound, id, err)
+	}
+
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := r2PrefixHasObjects(context.TODO(), client, bucketName, entry.prefix())
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrR2TrashEntryNotFound, id)
+	}
+
+	busy, err := r2PrefixBusy(countryCode, env, bucketName, entry.prefix())
+	if err != nil {
+		return nil, err
+	}
+	if busy {
+		return nil, fmt.Errorf("%w: %s is still being moved, restored or purged", ErrR2RestoreConflict, id)
+	}
+
+	occupied, err := r2PrefixHasObjects(context.TODO(), client, bucketName, entry.DirPath+"/")
+	if err != nil {
+		return nil, err
+	}
+	if occupied {
+		return nil, fmt.Errorf("%w: directory %s already has objects", ErrR2RestoreConflict, entry.DirPath)
+	}
+
+	logger.Info("Restoring trash entry %s to %s in bucket %s by %s", id, entry.DirPath, bucketName, operator)
+	return createJob(&model.Job{
+		Type:        model.JobTypeR2Move,
+		Environment: env,
+		CountryCode: countryCode,
+		BucketName:  bucketName,
+		SourceDir:   entry.prefix(),
+		TargetDir:   entry.DirPath + "/",
+		Concurrency: opts.Concurrency,
+		Operator:    operator,
+	})
+}
+
+// PurgeR2Trash 为回收站中超过保留时间的条目创建删除任务，已有未结束删除任务的条目沿用原任务，正在恢复的条目跳过
+func PurgeR2Trash(countryCode, env, bucketName, operator string) ([]model.Job, error) {
+	entries, err := ListR2Trash(countryCode, env, bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]model.Job, 0)
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.ExpiresAt.After(now) {
+			continue
+		}
+
+		job, err := findUnfinishedR2Job(countryCode, env, bucketName, model.JobTypeR2Delete, entry.prefix())
+		if err != nil {
+			return jobs, err
+		}
+		if job == nil {
+			busy, err := r2PrefixBusy(countryCode, env, bucketName, entry.prefix())
+			if err != nil {
+				return jobs, err
+			}
+			if busy {
+				logger.Info("Skipping trash entry %s of bucket %s, it is being restored", entry.ID, bucketName)
+				continue
+			}
+			if job, err = CreateR2DeleteJob(countryCode, env, bucketName, entry.prefix(), operator, R2BulkOptions{}); err != nil {
+				return jobs, err
+			}
+		}
+		jobs = append(jobs, *job)
+	}
+
+	logger.Info("Purging trash of bucket %s by %s: %d of %d entries expired", bucketName, operator, len(jobs), len(entries))
+	return jobs, nil
+}
+
+// StartR2TrashPurger 在后台定时清理所有移入过回收站的 bucket
+func StartR2TrashPurger() {
+	go func() {
+		ticker := time.NewTicker(r2TrashPurgeInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			purgeAllR2Trash()
+		}
+	}()
+}
+
+// purgeAllR2Trash 根据移入回收站的任务记录找到所有 bucket 并清理过期条目
+func purgeAllR2Trash() {
+	var buckets []model.Job
+	if err := db.DB.Model(&model.Job{}).
+		Distinct("environment", "country_code", "bucket_name").
+		Where("type = ? AND target_dir LIKE ?", model.JobTypeR2Move, R2TrashPrefix+"%").
+		Find(&buckets).Error; err != nil {
+		logger.Error("Failed to load buckets with trash: %v", err)
+		return
+	}
+
+	for _, bucket := range buckets {
+		if _, err := PurgeR2Trash(bucket.CountryCode, bucket.Environment, bucket.BucketName, r2TrashPurgeOperator); err != nil {
+			logger.Error("Failed to purge trash of bucket %s in %s: %v", bucket.BucketName, bucket.Environment, err)
+		}
+	}
+}
+
+// r2PrefixBusy 判断是否有未结束的任务正在读写该前缀
+func r2PrefixBusy(countryCode, env, bucketName, prefix string) (bool, error) {
+	var count int64
+	if err := db.DB.Model(&model.Job{}).
+		Where("environment = ? AND country_code = ? AND bucket_name = ? AND (source_dir = ? OR target_dir = ?) AND status IN ?",
+			env, countryCode, bucketName, prefix, prefix, []string{model.JobStatusPending, model.JobStatusRunning}).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check running jobs: %v", err)
+	}
+	return count > 0, nil
+}
+
+// parseR2TrashID 解析回收站条目 ID：<删除时间>[-<随机后缀>]/<转义后的目录>
+func parseR2TrashID(id string) (*R2TrashEntry, error) {
+	timestamp, escaped, ok := strings.Cut(strings.Trim(id, "/"), "/")
+	if !ok || escaped == "" || strings.Contains(escaped, "/") {
+		return nil, fmt.Errorf("invalid trash entry id %q", id)
+	}
+
+	// 没有后缀的条目由早期版本创建
+	deletedText, _, _ := strings.Cut(timestamp, "-")
+	deletedAt, err := time.Parse(r2TrashTimeFormat, deletedText)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trash entry time %q", timestamp)
+	}
+	dirPath, err := url.PathUnescape(escaped)
+	if err != nil || dirPath == "" {
+		return nil, fmt.Errorf("invalid trash entry directory %q", escaped)
+	}
+
+	return &R2TrashEntry{
+		ID:        timestamp + "/" + escaped,
+		DirPath:   dirPath,
+		DeletedAt: deletedAt,
+	}, nil
+}
+
+// listR2CommonPrefixes 列出前缀下一级的目录名
+func listR2CommonPrefixes(ctx context.Context, client *s3.Client, bucketName, prefix string) ([]string, error) {
+	names := make([]string, 0)
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(bucketName),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %v", err)
+		}
+		for _, commonPrefix := range page.CommonPrefixes {
+			names = append(names, strings.TrimSuffix(strings.TrimPrefix(aws.ToString(commonPrefix.Prefix), prefix), "/"))
+		}
+	}
+	return names, nil
+}
+
+// r2PrefixHasObjects 检查前缀下是否至少有一个对象
+func r2PrefixHasObjects(ctx context.Context, client *s3.Client, bucketName, prefix string) (bool, error) {
+	output, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucketName),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(1),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to list objects: %v", err)
+	}
+	return len(output.Contents) > 0, nil
+}
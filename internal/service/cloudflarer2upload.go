@@ -0,0 +1,657 @@
+package service
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"openapi/internal/config"
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// R2 归档格式
+const (
+	R2ArchiveTar   = "tar"
+	R2ArchiveTarGz = "tgz"
+	R2ArchiveZip   = "zip"
+)
+
+const (
+	// r2MaxParts 单个分片上传的最大分片数
+	r2MaxParts = 10000
+
+	// r2ArchiveWorkers 解压上传归档时的并发数
+	r2ArchiveWorkers = 8
+
+	// DefaultR2ArchiveMaxBytes 未配置 r2_archive_max_bytes 时归档及解压后的最大大小
+	DefaultR2ArchiveMaxBytes = 2 << 30
+
+	// DefaultR2ArchiveMaxEntries 未配置 r2_archive_max_entries 时最多解压的文件数
+	DefaultR2ArchiveMaxEntries = 50000
+)
+
+var (
+	// ErrR2ObjectNotFound 对象不存在
+	ErrR2ObjectNotFound = errors.New("R2 object not found")
+
+	// ErrR2InvalidRange Range 超出对象范围
+	ErrR2InvalidRange = errors.New("requested range not satisfiable")
+
+	// ErrR2ArchiveTooLarge 归档大小、解压后的总大小或文件数超过限制
+	ErrR2ArchiveTooLarge = errors.New("archive too large")
+)
+
+// r2ContentTypes 补充 mime 包内置表中缺少的静态站点常见类型
+var r2ContentTypes = map[string]string{
+	".txt":         "text/plain; charset=utf-8",
+	".map":         "application/json",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".otf":         "font/otf",
+	".webmanifest": "application/manifest+json",
+}
+
+// r2PartBuffers 复用分片上传的缓冲区
+var r2PartBuffers = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, constants.R2MultipartPartSize)
+		return &buf
+	},
+}
+
+// r2ObjectAttributes 上传时设置的对象属性，ContentType 为空时自动识别，其余为空时不设置
+type r2ObjectAttributes struct {
+	ContentType        string
+	CacheControl       string
+	ContentEncoding    string
+	ContentDisposition string
+	Metadata           map[string]string
+}
+
+// putInput 构造带对象属性的 PutObject 请求
+func (a r2ObjectAttributes) putInput(bucketName, key string) *s3.PutObjectInput {
+	return &s3.PutObjectInput{
+		Bucket:             aws.String(bucketName),
+		Key:                aws.String(key),
+		ContentType:        optionalString(a.ContentType),
+		CacheControl:       optionalString(a.CacheControl),
+		ContentEncoding:    optionalString(a.ContentEncoding),
+		ContentDisposition: optionalString(a.ContentDisposition),
+		Metadata:           a.Metadata,
+	}
+}
+
+// multipartInput 构造带对象属性的 CreateMultipartUpload 请求
+func (a r2ObjectAttributes) multipartInput(bucketName, key string) *s3.CreateMultipartUploadInput {
+	return &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(bucketName),
+		Key:                aws.String(key),
+		ContentType:        optionalString(a.ContentType),
+		CacheControl:       optionalString(a.CacheControl),
+		ContentEncoding:    optionalString(a.ContentEncoding),
+		ContentDisposition: optionalString(a.ContentDisposition),
+		Metadata:           a.Metadata,
+	}
+}
+
+// optionalString 空字符串返回 nil，避免发送空的请求头
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+// R2ArchiveUploadError 归档解压上传中途失败，本次已上传的文件会被删除
+type R2ArchiveUploadError struct {
+	Err       error    `json:"-"`
+	Uploaded  int      `json:"uploaded"`            // 失败前已上传的文件数
+	Removed   int      `json:"removed"`             // 已删除的文件数
+	Remaining []string `json:"remaining,omitempty"` // 删除失败、仍留在桶中的 key
+}
+
+func (e *R2ArchiveUploadError) Error() string {
+	msg := fmt.Sprintf("%v (removed %d of %d uploaded files", e.Err, e.Removed, e.Uploaded)
+	if len(e.Remaining) > 0 {
+		msg += fmt.Sprintf(", %d left in the bucket", len(e.Remaining))
+	}
+	return msg + ")"
+}
+
+func (e *R2ArchiveUploadError) Unwrap() error {
+	return e.Err
+}
+
+// R2ObjectReader 表示下载中的 R2 对象，调用方负责关闭 Body
+type R2ObjectReader struct {
+	Body          io.ReadCloser
+	ContentLength int64
+	ContentType   string
+	ContentRange  string // 非空表示部分内容
+	ETag          string
+	LastModified  time.Time
+}
+
+// R2ArchiveLimits 返回归档上传的最大大小和最多解压的文件数
+func R2ArchiveLimits() (int64, int) {
+	maxBytes := config.GlobalConfig.Cloudflare.R2ArchiveMaxBytes
+	if maxBytes <= 0 {
+		maxBytes = DefaultR2ArchiveMaxBytes
+	}
+	maxEntries := config.GlobalConfig.Cloudflare.R2ArchiveMaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultR2ArchiveMaxEntries
+	}
+	return maxBytes, maxEntries
+}
+
+// UploadR2Object 上传单个对象，contentType 为空时根据扩展名和内容自动识别
+func UploadR2Object(countryCode, env, bucketName, key, contentType string, body io.Reader) (*constants.R2UploadResult, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Uploading object %s to bucket %s", key, bucketName)
+
+	object, err := putR2Object(context.TODO(), client, bucketName, key, r2ObjectAttributes{ContentType: contentType}, body)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Uploaded object %s to bucket %s: %d bytes, multipart: %t", key, bucketName, object.Size, object.Multipart)
+
+	return &constants.R2UploadResult{
+		Bucket:    bucketName,
+		Count:     1,
+		TotalSize: object.Size,
+		Objects:   []constants.R2UploadedObject{*object},
+	}, nil
+}
+
+// UploadR2Archive 将 tar、tar.gz 或 zip 归档解压到 prefix 下，format 为空时根据文件头识别
+// 归档大小、解压后的总大小或文件数超过 R2ArchiveLimits 时返回 ErrR2ArchiveTooLarge
+// 中途失败时删除本次已上传的文件，返回 *R2ArchiveUploadError，其中列出删除失败的 key
+func UploadR2Archive(countryCode, env, bucketName, prefix, format string, body io.Reader) (*constants.R2UploadResult, error) {
+	reader := bufio.NewReader(body)
+	if format == "" {
+		format = detectArchiveFormat(reader)
+	}
+	if format != R2ArchiveTar && format != R2ArchiveTarGz && format != R2ArchiveZip {
+		return nil, fmt.Errorf("unsupported archive format: %s", format)
+	}
+
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if prefix != "" {
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+	}
+
+	logger.Info("Extracting %s archive to bucket %s under prefix %s", format, bucketName, prefix)
+
+	uploader := newR2ArchiveUploader(client, bucketName, prefix)
+	switch format {
+	case R2ArchiveZip:
+		err = uploader.extractZip(reader)
+	case R2ArchiveTarGz:
+		var gz *gzip.Reader
+		gz, err = gzip.NewReader(reader)
+		if err != nil {
+			err = fmt.Errorf("failed to read gzip archive: %v", err)
+			break
+		}
+		err = uploader.extractTar(gz)
+		gz.Close()
+	default:
+		err = uploader.extractTar(reader)
+	}
+
+	result, uploadErr := uploader.finish()
+	if err == nil {
+		err = uploadErr
+	}
+	if err != nil {
+		return nil, uploader.rollback(err)
+	}
+
+	logger.Info("Extracted %d objects (%d bytes) to bucket %s under prefix %s",
+		result.Count, result.TotalSize, bucketName, prefix)
+	return result, nil
+}
+
+// GetR2Object 读取对象，byteRange 为 HTTP Range 头（如 bytes=0-1023），为空时读取整个对象
+func GetR2Object(countryCode, env, bucketName, key, byteRange string) (*R2ObjectReader, error) {
+	client, _, err := createR2ClientFor(env, countryCode)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}
+	if byteRange != "" {
+		input.Range = aws.String(byteRange)
+	}
+
+	output, err := client.GetObject(context.TODO(), input)
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) {
+			switch respErr.HTTPStatusCode() {
+			case http.StatusNotFound:
+				return nil, fmt.Errorf("%w: %s", ErrR2ObjectNotFound, key)
+			case http.StatusRequestedRangeNotSatisfiable:
+				return nil, fmt.Errorf("%w: %s", ErrR2InvalidRange, byteRange)
+			}
+		}
+		return nil, fmt.Errorf("failed to get object %s: %v", key, err)
+	}
+
+	return &R2ObjectReader{
+		Body:          output.Body,
+		ContentLength: aws.ToInt64(output.ContentLength),
+		ContentType:   aws.ToString(output.ContentType),
+		ContentRange:  aws.ToString(output.ContentRange),
+		ETag:          aws.ToString(output.ETag),
+		LastModified:  aws.ToTime(output.LastModified),
+	}, nil
+}
+
+// putR2Object 上传大小未知的对象，不超过一个分片大小时直接上传，否则使用分片上传
+func putR2Object(ctx context.Context, client *s3.Client, bucketName, key string, attrs r2ObjectAttributes, body io.Reader) (*constants.R2UploadedObject, error) {
+	buf := r2PartBuffers.Get().(*[]byte)
+	defer r2PartBuffers.Put(buf)
+	part := *buf
+
+	n, err := io.ReadFull(body, part)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return putR2ObjectBytes(ctx, client, bucketName, key, attrs, part[:n])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body of %s: %v", key, err)
+	}
+	if attrs.ContentType == "" {
+		attrs.ContentType = detectContentType(key, part[:n])
+	}
+	contentType := attrs.ContentType
+
+	created, err := client.CreateMultipartUpload(ctx, attrs.multipartInput(bucketName, key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart upload for %s: %v", key, err)
+	}
+
+	abort := func(cause error) error {
+		// 请求可能已被取消，使用独立的 context 清理未完成的上传
+		if _, err := client.AbortMultipartUpload(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucketName),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		}); err != nil {
+			logger.Error("Failed to abort multipart upload of %s: %v", key, err)
+		}
+		return cause
+	}
+
+	var size int64
+	parts := make([]types.CompletedPart, 0)
+	for partNumber := int32(1); n > 0; partNumber++ {
+		if partNumber > r2MaxParts {
+			return nil, abort(fmt.Errorf("object %s exceeds %d parts", key, r2MaxParts))
+		}
+
+		output, err := client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(bucketName),
+			Key:           aws.String(key),
+			UploadId:      created.UploadId,
+			PartNumber:    aws.Int32(partNumber),
+			Body:          bytes.NewReader(part[:n]),
+			ContentLength: aws.Int64(int64(n)),
+		})
+		if err != nil {
+			return nil, abort(fmt.Errorf("failed to upload part %d of %s: %v", partNumber, key, err))
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:       output.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+		size += int64(n)
+
+		n, err = io.ReadFull(body, part)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return nil, abort(fmt.Errorf("failed to read body of %s: %v", key, err))
+		}
+	}
+
+	completed, err := client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucketName),
+		Key:             aws.String(key),
+		UploadId:        created.UploadId,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return nil, abort(fmt.Errorf("failed to complete multipart upload of %s: %v", key, err))
+	}
+
+	return &constants.R2UploadedObject{
+		Key:         key,
+		Size:        size,
+		ContentType: contentType,
+		ETag:        strings.Trim(aws.ToString(completed.ETag), `"`),
+		Multipart:   true,
+	}, nil
+}
+
+// putR2ObjectBytes 直接上传内存中的对象
+func putR2ObjectBytes(ctx context.Context, client *s3.Client, bucketName, key string, attrs r2ObjectAttributes, data []byte) (*constants.R2UploadedObject, error) {
+	if attrs.ContentType == "" {
+		attrs.ContentType = detectContentType(key, data)
+	}
+	return putR2ObjectSized(ctx, client, bucketName, key, attrs, bytes.NewReader(data), int64(len(data)))
+}
+
+// putR2ObjectSized 将已知大小的对象直接流式上传，不经过分片缓冲区，size 不能超过单次上传的上限
+func putR2ObjectSized(ctx context.Context, client *s3.Client, bucketName, key string, attrs r2ObjectAttributes, body io.Reader, size int64) (*constants.R2UploadedObject, error) {
+	input := attrs.putInput(bucketName, key)
+	input.Body = body
+	input.ContentLength = aws.Int64(size)
+
+	output, err := client.PutObject(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to put object %s: %v", key, err)
+	}
+
+	return &constants.R2UploadedObject{
+		Key:         key,
+		Size:        size,
+		ContentType: attrs.ContentType,
+		ETag:        strings.Trim(aws.ToString(output.ETag), `"`),
+	}, nil
+}
+
+// detectContentType 优先按扩展名识别 Content-Type，无法识别时根据内容判断
+func detectContentType(key string, head []byte) string {
+	ext := strings.ToLower(path.Ext(key))
+	if contentType, ok := r2ContentTypes[ext]; ok {
+		return contentType
+	}
+	if contentType := mime.TypeByExtension(ext); contentType != "" {
+		return contentType
+	}
+	return http.DetectContentType(head)
+}
+
+// detectArchiveFormat 根据文件头识别归档格式，无法识别时按 tar 处理
+func detectArchiveFormat(reader *bufio.Reader) string {
+	head, _ := reader.Peek(4)
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), bytes.HasPrefix(head, []byte("PK\x05\x06")):
+		return R2ArchiveZip
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		return R2ArchiveTarGz
+	default:
+		return R2ArchiveTar
+	}
+}
+
+// archiveEntryKey 将归档内的路径转换为对象 key，去掉开头的 ./ 和 / 并消除 ..
+func archiveEntryKey(prefix, name string) (string, bool) {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	if name == "" || name == "." {
+		return "", false
+	}
+	return prefix + name, true
+}
+
+// r2ArchiveEntry 等待上传的归档文件
+type r2ArchiveEntry struct {
+	key  string
+	data []byte
+}
+
+// r2ArchiveUploader 并发上传解压出的文件，小文件交给工作池，大文件在读取归档时直接分片上传
+type r2ArchiveUploader struct {
+	client     *s3.Client
+	bucketName string
+	prefix     string
+
+	// 只在读取归档的 goroutine 中访问
+	maxBytes   int64
+	maxEntries int
+	bytes      int64
+	entries    int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	tasks  chan r2ArchiveEntry
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	objects []constants.R2UploadedObject
+	err     error
+}
+
+// newR2ArchiveUploader 创建归档上传器并启动工作池
+func newR2ArchiveUploader(client *s3.Client, bucketName, prefix string) *r2ArchiveUploader {
+	ctx, cancel := context.WithCancel(context.TODO())
+	maxBytes, maxEntries := R2ArchiveLimits()
+	u := &r2ArchiveUploader{
+		client:     client,
+		bucketName: bucketName,
+		prefix:     prefix,
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		ctx:        ctx,
+		cancel:     cancel,
+		tasks:      make(chan r2ArchiveEntry),
+		objects:    make([]constants.R2UploadedObject, 0),
+	}
+
+	for i := 0; i < r2ArchiveWorkers; i++ {
+		u.wg.Add(1)
+		go func() {
+			defer u.wg.Done()
+			for entry := range u.tasks {
+				object, err := putR2ObjectBytes(u.ctx, u.client, u.bucketName, entry.key, r2ObjectAttributes{}, entry.data)
+				u.record(object, err)
+			}
+		}()
+	}
+	return u
+}
+
+// add 上传一个归档文件，出现错误后停止接收新文件
+// size 为归档中记录的大小，tar 和 zip 读取时都不会返回超过该大小的内容
+func (u *r2ArchiveUploader) add(name string, size int64, reader io.Reader) error {
+	key, ok := archiveEntryKey(u.prefix, name)
+	if !ok {
+		return nil
+	}
+	if err := u.firstErr(); err != nil {
+		return err
+	}
+
+	u.entries++
+	if u.entries > u.maxEntries {
+		return fmt.Errorf("%w: more than %d files", ErrR2ArchiveTooLarge, u.maxEntries)
+	}
+	u.bytes += size
+	if size < 0 || u.bytes > u.maxBytes {
+		return fmt.Errorf("%w: extracted size exceeds %d bytes", ErrR2ArchiveTooLarge, u.maxBytes)
+	}
+
+	if size > constants.R2MultipartPartSize {
+		object, err := putR2Object(u.ctx, u.client, u.bucketName, key, r2ObjectAttributes{}, reader)
+		u.record(object, err)
+		return err
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read %s from archive: %v", name, err)
+	}
+	select {
+	case u.tasks <- r2ArchiveEntry{key: key, data: data}:
+		return nil
+	case <-u.ctx.Done():
+		return u.firstErr()
+	}
+}
+
+// record 记录上传结果，第一个错误会取消其余上传
+func (u *r2ArchiveUploader) record(object *constants.R2UploadedObject, err error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if err != nil {
+		if u.err == nil {
+			u.err = err
+			u.cancel()
+		}
+		return
+	}
+	u.objects = append(u.objects, *object)
+}
+
+// firstErr 返回第一个上传错误
+func (u *r2ArchiveUploader) firstErr() error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.err
+}
+
+// finish 等待所有上传完成并汇总结果
+func (u *r2ArchiveUploader) finish() (*constants.R2UploadResult, error) {
+	close(u.tasks)
+	u.wg.Wait()
+	u.cancel()
+
+	if err := u.firstErr(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(u.objects, func(i, j int) bool {
+		return u.objects[i].Key < u.objects[j].Key
+	})
+	result := &constants.R2UploadResult{
+		Bucket:  u.bucketName,
+		Prefix:  u.prefix,
+		Count:   len(u.objects),
+		Objects: u.objects,
+	}
+	for _, object := range u.objects {
+		result.TotalSize += object.Size
+	}
+	return result, nil
+}
+
+// rollback 在 finish 之后调用，删除本次已上传的文件，没有上传任何文件时直接返回 cause
+func (u *r2ArchiveUploader) rollback(cause error) error {
+	if len(u.objects) == 0 {
+		return cause
+	}
+
+	items := make(chan r2BulkItem)
+	go func() {
+		defer close(items)
+		for _, object := range u.objects {
+			items <- r2BulkItem{Key: object.Key, Size: object.Size}
+		}
+	}()
+
+	// 上传的 context 已被取消，使用独立的 context 清理
+	summary := runR2BatchDelete(context.Background(), u.client, u.bucketName, items, R2BulkOptions{})
+
+	archiveErr := &R2ArchiveUploadError{Err: cause, Uploaded: len(u.objects), Removed: summary.Succeeded}
+	for _, result := range summary.Results {
+		if result.Status != R2KeySucceeded {
+			archiveErr.Remaining = append(archiveErr.Remaining, result.Key)
+		}
+	}
+	logger.Error("Archive upload to bucket %s under prefix %s failed, removed %d of %d uploaded files: %v",
+		u.bucketName, u.prefix, archiveErr.Removed, archiveErr.Uploaded, cause)
+	return archiveErr
+}
+
+// extractTar 读取 tar 归档中的普通文件并上传
+func (u *r2ArchiveUploader) extractTar(reader io.Reader) error {
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar archive: %v", err)
+		}
+		if !header.FileInfo().Mode().IsRegular() {
+			continue
+		}
+		if err := u.add(header.Name, header.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip 读取 zip 归档中的普通文件并上传，zip 需要随机读取，先写入临时文件
+func (u *r2ArchiveUploader) extractZip(reader io.Reader) error {
+	tmp, err := os.CreateTemp("", "r2-archive-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, io.LimitReader(reader, u.maxBytes+1))
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %v", err)
+	}
+	if size > u.maxBytes {
+		return fmt.Errorf("%w: archive exceeds %d bytes", ErrR2ArchiveTooLarge, u.maxBytes)
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip archive: %v", err)
+	}
+
+	for _, file := range zr.File {
+		if !file.Mode().IsRegular() {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return fmt.Errorf("failed to open %s in zip archive: %v", file.Name, err)
+		}
+		err = u.add(file.Name, int64(file.UncompressedSize64), rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"openapi/internal/logger"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// R2 校验发现的不一致类型
+const (
+	R2MismatchMissing  = "missing"  // 目标中缺少
+	R2MismatchExtra    = "extra"    // 目标中多出
+	R2MismatchSize     = "size"     // 大小不同
+	R2MismatchETag     = "etag"     // ETag 不同
+	R2MismatchChecksum = "checksum" // 分片上传对象内容的 MD5 不同
+)
+
+// R2VerifyMaxMismatches 校验结果中返回的最大不一致条数，超出部分只计数
+const R2VerifyMaxMismatches = 1000
+
+// ErrR2VerifyMismatch 校验发现目标与源不一致
+var ErrR2VerifyMismatch = errors.New("target does not match source")
+
+// R2Mismatch 单个对象的不一致，Key 为相对前缀的路径
+type R2Mismatch struct {
+	Key        string `json:"key"`
+	Type       string `json:"type"`
+	SourceSize int64  `json:"source_size,omitempty"`
+	TargetSize int64  `json:"target_size,omitempty"`
+	SourceETag string `json:"source_etag,omitempty"`
+	TargetETag string `json:"target_etag,omitempty"`
+}
+
+// R2VerifyResult 源前缀与目标前缀的校验结果
+type R2VerifyResult struct {
+	Source        R2Location   `json:"source"`
+	Target        R2Location   `json:"target"`
+	Match         bool         `json:"match"`
+	SourceObjects int          `json:"source_objects"`
+	SourceBytes   int64        `json:"source_bytes"`
+	TargetObjects int          `json:"target_objects"`
+	TargetBytes   int64        `json:"target_bytes"`
+	Matched       int          `json:"matched"`
+	Checksummed   int          `json:"checksummed"` // ETag 无法直接比较、下载内容计算 MD5 比较的对象数
+	MismatchCount int          `json:"mismatch_count"`
+	Mismatches    []R2Mismatch `json:"mismatches"` // 按 key 排序，最多 R2VerifyMaxMismatches 条
+}
+
+// err 有不一致时返回 ErrR2VerifyMismatch
+func (r *R2VerifyResult) err() error {
+	if r.Match {
+		return nil
+	}
+	return fmt.Errorf("%w: %d mismatches between %s and %s", ErrR2VerifyMismatch, r.MismatchCount, r.Source.Prefix, r.Target.Prefix)
+}
+
+// VerifyR2Objects 比较源前缀和目标前缀下的对象数量、大小和 ETag，两端可以属于不同环境和 Cloudflare 账号
+// 只有出错时返回 error，不一致通过结果中的 Match 和 Mismatches 返回
+func VerifyR2Objects(source, target R2Location) (*R2VerifyResult, error) {
+	source.Prefix = normalizeR2Prefix(source.Prefix)
+	target.Prefix = normalizeR2Prefix(target.Prefix)
+
+	sourceClient, _, err := createR2ClientFor(source.Env, source.CountryCode)
+	if err != nil {
+		return nil, err
+	}
+	targetClient := sourceClient
+	if target.Env != source.Env || target.CountryCode != source.CountryCode {
+		if targetClient, _, err = createR2ClientFor(target.Env, target.CountryCode); err != nil {
+			return nil, err
+		}
+	}
+
+	return verifyR2Prefixes(context.TODO(), sourceClient, targetClient, source, target)
+}
+
+// verifyR2Prefixes 列出两端的对象并逐个比较
+// 大小相同但 ETag 不同且至少一端为分片上传时，下载两端内容比较 MD5
+func verifyR2Prefixes(ctx context.Context, sourceClient, targetClient *s3.Client, source, target R2Location) (*R2VerifyResult, error) {
+	sourceIndex, err := listR2ObjectIndex(ctx, sourceClient, source.BucketName, source.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	targetIndex, err := listR2ObjectIndex(ctx, targetClient, target.BucketName, target.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &R2VerifyResult{
+		Source:        source,
+		Target:        target,
+		SourceObjects: len(sourceIndex),
+		TargetObjects: len(targetIndex),
+		Mismatches:    make([]R2Mismatch, 0),
+	}
+	var mismatches []R2Mismatch
+	checksums := make([]r2BulkItem, 0)
+
+	for relative, sourceItem := range sourceIndex {
+		result.SourceBytes += sourceItem.Size
+		targetItem, ok := targetIndex[relative]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, R2Mismatch{Key: relative, Type: R2MismatchMissing, SourceSize: sourceItem.Size, SourceETag: sourceItem.ETag})
+		case sourceItem.Size != targetItem.Size:
+			mismatches = append(mismatches, newR2Mismatch(relative, R2MismatchSize, sourceItem, targetItem))
+		case sourceItem.ETag == targetItem.ETag:
+			result.Matched++
+		case isMultipartETag(sourceItem.ETag) || isMultipartETag(targetItem.ETag):
+			checksums = append(checksums, r2BulkItem{Key: relative, Size: sourceItem.Size})
+		default:
+			mismatches = append(mismatches, newR2Mismatch(relative, R2MismatchETag, sourceItem, targetItem))
+		}
+	}
+	for relative, targetItem := range targetIndex {
+		result.TargetBytes += targetItem.Size
+		if _, ok := sourceIndex[relative]; !ok {
+			mismatches = append(mismatches, R2Mismatch{Key: relative, Type: R2MismatchExtra, TargetSize: targetItem.Size, TargetETag: targetItem.ETag})
+		}
+	}
+
+	if len(checksums) > 0 {
+		checksumMismatches, err := compareR2Checksums(ctx, sourceClient, targetClient, source, target, checksums, sourceIndex, targetIndex)
+		if err != nil {
+			return nil, err
+		}
+		result.Checksummed = len(checksums)
+		result.Matched += len(checksums) - len(checksumMismatches)
+		mismatches = append(mismatches, checksumMismatches...)
+	}
+
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Key < mismatches[j].Key
+	})
+	result.MismatchCount = len(mismatches)
+	result.Match = len(mismatches) == 0
+	if len(mismatches) > R2VerifyMaxMismatches {
+		mismatches = mismatches[:R2VerifyMaxMismatches]
+	}
+	result.Mismatches = append(result.Mismatches, mismatches...)
+
+	logger.Info("Verified %s/%s against %s/%s: %d source objects, %d target objects, %d matched, %d mismatches",
+		source.BucketName, source.Prefix, target.BucketName, target.Prefix,
+		result.SourceObjects, result.TargetObjects, result.Matched, result.MismatchCount)
+	return result, nil
+}
+
+// compareR2Checksums 并发下载两端对象计算 MD5，返回内容不同的对象
+func compareR2Checksums(ctx context.Context, sourceClient, targetClient *s3.Client, source, target R2Location, items []r2BulkItem, sourceIndex, targetIndex map[string]r2BulkItem) ([]R2Mismatch, error) {
+	queue := make(chan r2BulkItem, len(items))
+	for _, item := range items {
+		queue <- item
+	}
+	close(queue)
+
+	var (
+		mu         sync.Mutex
+		mismatches = make([]R2Mismatch, 0)
+	)
+	summary := runR2Bulk(ctx, queue, R2BulkOptions{}, func(ctx context.Context, item r2BulkItem) error {
+		sourceSum, err := r2ObjectMD5(ctx, sourceClient, source.BucketName, source.Prefix+item.Key)
+		if err != nil {
+			return err
+		}
+		targetSum, err := r2ObjectMD5(ctx, targetClient, target.BucketName, target.Prefix+item.Key)
+		if err != nil {
+			return err
+		}
+		if sourceSum != targetSum {
+			mu.Lock()
+			mismatches = append(mismatches, newR2Mismatch(item.Key, R2MismatchChecksum, sourceIndex[item.Key], targetIndex[item.Key]))
+			mu.Unlock()
+		}
+		return nil
+	})
+	if summary.Failed > 0 || summary.Skipped > 0 {
+		return nil, fmt.Errorf("failed to compare checksums of %d of %d objects", summary.Failed+summary.Skipped, summary.Total)
+	}
+	return mismatches, nil
+}
+
+// r2ObjectMD5 下载对象并计算内容的 MD5
+func r2ObjectMD5(ctx context.Context, client *s3.Client, bucketName, key string) (string, error) {
+	output, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get object %s/%s: %w", bucketName, key, err)
+	}
+	defer output.Body.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, output.Body); err != nil {
+		return "", fmt.Errorf("failed to read object %s/%s: %w", bucketName, key, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newR2Mismatch 创建两端都存在的对象的不一致记录
+func newR2Mismatch(key, mismatchType string, source, target r2BulkItem) R2Mismatch {
+	return R2Mismatch{
+		Key:        key,
+		Type:       mismatchType,
+		SourceSize: source.Size,
+		TargetSize: target.Size,
+		SourceETag: source.ETag,
+		TargetETag: target.ETag,
+	}
+}
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"errors"
+	"fmt"
+
 	"openapi/internal/db"
 	"openapi/internal/model"
+
+	"gorm.io/gorm"
 )
 
 // LoadAliyunConfig 从数据库加载激活的阿里云配置
@@ -25,3 +30,16 @@ func LoadCloudflareConfig() (*model.CloudflareAccountInfo, error) {
 	err := db.DB.Where("is_active = ?", true).First(&config).Error
 	return &config, err
 }
+
+// loadCloudflareAccount 按环境和国家加载激活的 Cloudflare 账号，该国家没有单独账号时使用环境的默认账号
+func loadCloudflareAccount(env, countryCode string) (*model.CloudflareAccountInfo, error) {
+	var account model.CloudflareAccountInfo
+	err := db.DB.Where("environment = ? AND country_code = ? AND is_active = ?", env, countryCode, true).First(&account).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = db.DB.Where("environment = ? AND is_active = ?", env, true).First(&account).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cloudflare config for env %s, country %s: %v", env, countryCode, err)
+	}
+	return &account, nil
+}
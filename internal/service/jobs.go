@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrJobNotFound 任务不存在
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrJobFinished 任务已结束，不能取消
+	ErrJobFinished = errors.New("job already finished")
+)
+
+// runningJobs 当前进程中正在执行的任务及其取消函数
+var runningJobs = struct {
+	sync.Mutex
+	cancels map[uint]context.CancelFunc
+}{cancels: make(map[uint]context.CancelFunc)}
+
+// createJob 保存任务并在后台开始执行
+func createJob(job *model.Job) (*model.Job, error) {
+	job.Status = model.JobStatusPending
+	if err := db.DB.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+
+	logger.Info("Created %s job %d for %s/%s by %s", job.Type, job.ID, job.BucketName, job.SourceDir, job.Operator)
+	startJob(*job)
+	return job, nil
+}
+
+// startJob 在后台执行任务，同一任务在进程内只会执行一次
+func startJob(job model.Job) {
+	runningJobs.Lock()
+	if _, ok := runningJobs.cancels[job.ID]; ok {
+		runningJobs.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	runningJobs.cancels[job.ID] = cancel
+	runningJobs.Unlock()
+
+	go func() {
+		defer func() {
+			runningJobs.Lock()
+			delete(runningJobs.cancels, job.ID)
+			runningJobs.Unlock()
+			cancel()
+		}()
+
+		started, err := markJobRunning(&job)
+		if err != nil {
+			logger.Error("Failed to start job %d: %v", job.ID, err)
+			return
+		}
+		if !started {
+			logger.Info("Job %d was cancelled before it started", job.ID)
+			return
+		}
+
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("job panicked: %v", r)
+				}
+			}()
+			err = runJob(ctx, &job)
+		}()
+		finishJob(ctx, &job, err)
+	}()
+}
+
+// markJobRunning 将未结束的任务标记为执行中，任务已被取消时返回 false
+func markJobRunning(job *model.Job) (bool, error) {
+	now := time.Now()
+	if job.StartedAt == nil {
+		job.StartedAt = &now
+	}
+	result := db.DB.Model(job).Where("status IN ?", []string{model.JobStatusPending, model.JobStatusRunning}).
+		Updates(map[string]interface{}{"status": model.JobStatusRunning, "started_at": job.StartedAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	job.Status = model.JobStatusRunning
+	return result.RowsAffected > 0, nil
+}
+
+// runJob 按任务类型执行任务
+func runJob(ctx context.Context, job *model.Job) error {
+	switch job.Type {
+	case model.JobTypeR2Copy, model.JobTypeR2Delete, model.JobTypeR2Move:
+		return runR2Job(ctx, job)
+	case model.JobTypeRelease:
+		return runReleaseJob(ctx, job)
+	default:
+		return fmt.Errorf("unknown job type: %s", job.Type)
+	}
+}
+
+// finishJob 记录任务的最终状态，已被取消的任务不会被覆盖为成功或失败
+func finishJob(ctx context.Context, job *model.Job, err error) {
+	now := time.Now()
+	job.FinishedAt = &now
+
+	query := db.DB.Model(job)
+	switch {
+	case ctx.Err() != nil:
+		job.Status = model.JobStatusCancelled
+	case err != nil:
+		job.Status = model.JobStatusFailed
+		job.Error = err.Error()
+	case job.FailedObjects > 0:
+		job.Status = model.JobStatusFailed
+		job.Error = fmt.Sprintf("%d objects failed", job.FailedObjects)
+	default:
+		job.Status = model.JobStatusSucceeded
+	}
+	if job.Status != model.JobStatusCancelled {
+		query = query.Where("status = ?", model.JobStatusRunning)
+	}
+
+	if dbErr := query.Select("status", "error", "finished_at", "total_objects", "total_bytes",
+		"done_objects", "done_bytes", "failed_objects", "skipped_objects", "unchanged_objects", "deleted_objects",
+		"last_key", "errors").Updates(job).Error; dbErr != nil {
+		logger.Error("Failed to finish job %d: %v", job.ID, dbErr)
+	}
+
+	logger.Info("Job %d %s: %d/%d objects done, %d failed", job.ID, job.Status, job.DoneObjects, job.TotalObjects, job.FailedObjects)
+}
+
+// saveJobProgress 保存任务进度，失败时只记录日志
+func saveJobProgress(job *model.Job) {
+	if err := db.DB.Model(job).Select("total_objects", "total_bytes", "done_objects", "done_bytes",
+		"failed_objects", "skipped_objects", "unchanged_objects", "deleted_objects", "last_key", "errors").Updates(job).Error; err != nil {
+		logger.Error("Failed to save progress of job %d: %v", job.ID, err)
+	}
+}
+
+// recordJobError 记录单个对象的错误，超过 JobMaxErrors 条后只计数
+func recordJobError(job *model.Job, key, message string) {
+	job.FailedObjects++
+	if len(job.Errors) < model.JobMaxErrors {
+		job.Errors = append(job.Errors, model.JobError{Key: key, Error: message})
+	}
+}
+
+// GetJob 获取任务
+func GetJob(id uint) (*model.Job, error) {
+	var job model.Job
+	err := db.DB.First(&job, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: %d", ErrJobNotFound, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %d: %v", id, err)
+	}
+	return &job, nil
+}
+
+// ListJobs 获取任务列表，按时间倒序，参数为空时不过滤
+func ListJobs(env, jobType, status string, limit int) ([]model.Job, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := db.DB.Model(&model.Job{})
+	if env != "" {
+		query = query.Where("environment = ?", env)
+	}
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var jobs []model.Job
+	err := query.Order("id DESC").Limit(limit).Find(&jobs).Error
+	return jobs, err
+}
+
+// CancelJob 取消任务，正在执行的任务会在当前对象处理完后停止
+func CancelJob(id uint, operator string) (*model.Job, error) {
+	job, err := GetJob(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return job, fmt.Errorf("%w: job %d is %s", ErrJobFinished, id, job.Status)
+	}
+
+	runningJobs.Lock()
+	cancel, ok := runningJobs.cancels[id]
+	runningJobs.Unlock()
+	if ok {
+		cancel()
+	}
+
+	if err := db.DB.Model(&model.Job{}).
+		Where("id = ? AND status IN ?", id, []string{model.JobStatusPending, model.JobStatusRunning}).
+		Updates(map[string]interface{}{"status": model.JobStatusCancelled, "finished_at": time.Now()}).Error; err != nil {
+		return nil, fmt.Errorf("failed to cancel job %d: %v", id, err)
+	}
+
+	logger.Info("Job %d cancelled by %s", id, operator)
+	return GetJob(id)
+}
+
+// ResumeJobs 服务启动时继续执行未结束的任务，从记录的最后完成的 key 之后开始
+func ResumeJobs() error {
+	var jobs []model.Job
+	if err := db.DB.Where("status IN ?", []string{model.JobStatusPending, model.JobStatusRunning}).
+		Order("id").Find(&jobs).Error; err != nil {
+		return fmt.Errorf("failed to load unfinished jobs: %v", err)
+	}
+
+	for _, job := range jobs {
+		logger.Info("Resuming %s job %d after key %q", job.Type, job.ID, job.LastKey)
+		startJob(job)
+	}
+	return nil
+}
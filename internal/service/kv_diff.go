@@ -0,0 +1,100 @@
+package service
+
+import (
+	"strings"
+)
+
+// 差异行类型
+const (
+	DiffOpEqual  = " "
+	DiffOpInsert = "+"
+	DiffOpDelete = "-"
+)
+
+// maxDiffLines 超过该行数时不做逐行比对，直接整体替换
+const maxDiffLines = 5000
+
+// DiffLine 表示一行差异
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// DiffLines 按行比较两个文本，返回逐行差异
+func DiffLines(from, to string) []DiffLine {
+	a := splitLines(from)
+	b := splitLines(to)
+
+	// 文本过大时退化为整体替换，避免 LCS 占用过多内存
+	if len(a) > maxDiffLines || len(b) > maxDiffLines {
+		lines := make([]DiffLine, 0, len(a)+len(b))
+		for _, line := range a {
+			lines = append(lines, DiffLine{Op: DiffOpDelete, Text: line})
+		}
+		for _, line := range b {
+			lines = append(lines, DiffLine{Op: DiffOpInsert, Text: line})
+		}
+		return lines
+	}
+
+	// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffOpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffOpDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffOpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, DiffLine{Op: DiffOpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, DiffLine{Op: DiffOpInsert, Text: b[j]})
+	}
+
+	return lines
+}
+
+// FormatDiff 将差异行格式化为文本，每行以操作符开头
+func FormatDiff(lines []DiffLine) string {
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line.Op)
+		sb.WriteString(line.Text)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// splitLines 按换行符拆分文本，空文本返回空切片
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// KV 导出格式
+const (
+	KVExportFormatNDJSON = "ndjson"
+	KVExportFormatJSON   = "json"
+)
+
+// KV 导入冲突处理方式
+const (
+	KVImportModeSkip      = "skip"      // 目标中已存在的 key 跳过
+	KVImportModeOverwrite = "overwrite" // 覆盖已存在的 key
+	KVImportModeFail      = "fail"      // 存在任一冲突时不写入任何 key
+)
+
+// kvExportBatchSize 导出时每批读取的 key 数量
+const kvExportBatchSize = 100
+
+// KVImportConflictError 导入的 key 在目标命名空间中已存在
+type KVImportConflictError struct {
+	NamespaceID string   `json:"namespace_id"`
+	Keys        []string `json:"keys"`
+}
+
+func (e *KVImportConflictError) Error() string {
+	return fmt.Sprintf("%d keys already exist in namespace %s", len(e.Keys), e.NamespaceID)
+}
+
+// KVExportArchive JSON 格式的导出文件
+type KVExportArchive struct {
+	NamespaceID string                 `json:"namespace_id"`
+	ExportedAt  time.Time              `json:"exported_at"`
+	Items       []constants.KVBulkPair `json:"items"`
+	Trailer     *KVExportTrailer       `json:"trailer,omitempty"`
+}
+
+// KVExportTrailer 导出文件末尾的结束记录，导入时据此确认文件完整
+// NDJSON 格式为最后一行 {"trailer":{...}}，JSON 格式为 items 之后的 trailer 字段
+type KVExportTrailer struct {
+	Count int    `json:"count"`           // 实际导出的 key 数量
+	Error string `json:"error,omitempty"` // 导出中途失败的原因，非空时文件不完整
+}
+
+// ErrKVExportIncomplete 导入的文件缺少结束记录、记录了导出失败或条目数与结束记录不符
+var ErrKVExportIncomplete = errors.New("export file is incomplete")
+
+// KVImportResult 导入结果
+type KVImportResult struct {
+	Total   int                     `json:"total"`
+	Skipped []string                `json:"skipped,omitempty"` // skip 模式下已存在而跳过的 key
+	Expired []string                `json:"expired,omitempty"` // 已过期而跳过的 key
+	Write   *constants.KVBulkResult `json:"write,omitempty"`
+}
+
+// KVExport 表示一次准备好的 KV 命名空间导出
+type KVExport struct {
+	client      *CloudflareClient
+	accountID   string
+	namespaceId string
+	format      string
+	keys        []constants.KVKeys
+}
+
+// PrepareKVExport 列出命名空间中的所有 key，返回的 KVExport 通过 WriteTo 输出导出内容
+func PrepareKVExport(countryCode, env, namespaceId, format string) (*KVExport, error) {
+	if format == "" {
+		format = KVExportFormatNDJSON
+	}
+	if format != KVExportFormatNDJSON && format != KVExportFormatJSON {
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+
+	keysResp, err := GetKVKeys(countryCode, env, namespaceId, KVKeysListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list keys: %v", err)
+	}
+
+	// GetKVKeys 已加载该环境的配置
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KVExport{
+		client:      client,
+		accountID:   accountID,
+		namespaceId: namespaceId,
+		format:      format,
+		keys:        keysResp.Result,
+	}, nil
+}
+
+// Count 返回导出的 key 数量
+func (e *KVExport) Count() int {
+	return len(e.keys)
+}
+
+// Format 返回导出格式
+func (e *KVExport) Format() string {
+	return e.format
+}
+
+// WriteTo 分批读取 key 的值并写入 w，读取期间被删除的 key 不会导出
+// 内容之后总会尝试写入结束记录，读取失败时结束记录带上错误，导入时会拒绝该文件
+func (e *KVExport) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	counter := &countingWriter{w: bw}
+
+	if e.format == KVExportFormatJSON {
+		header, err := json.Marshal(KVExportArchive{
+			NamespaceID: e.namespaceId,
+			ExportedAt:  time.Now().UTC(),
+		})
+		if err != nil {
+			return counter.n, err
+		}
+		// 去掉末尾的 "items":null}，逐条写入 items
+		prefix := strings.TrimSuffix(string(header), `"items":null}`)
+		if _, err := io.WriteString(counter, prefix+`"items":[`); err != nil {
+			return counter.n, err
+		}
+	}
+
+	exported, exportErr := e.writeItems(counter, bw)
+
+	trailer := KVExportTrailer{Count: exported}
+	if exportErr != nil {
+		trailer.Error = exportErr.Error()
+	}
+	if err := e.writeTrailer(counter, trailer); err != nil && exportErr == nil {
+		exportErr = err
+	}
+	if err := bw.Flush(); err != nil && exportErr == nil {
+		exportErr = err
+	}
+	if exportErr != nil {
+		return counter.n, exportErr
+	}
+
+	logger.Info("Exported %d of %d KV keys from namespace %s as %s", exported, len(e.keys), e.namespaceId, e.format)
+	return counter.n, nil
+}
+
+// writeItems 分批读取并写入所有条目，返回已写入的条目数
+func (e *KVExport) writeItems(w io.Writer, bw *bufio.Writer) (int, error) {
+	exported := 0
+	for start := 0; start < len(e.keys); start += kvExportBatchSize {
+		end := start + kvExportBatchSize
+		if end > len(e.keys) {
+			end = len(e.keys)
+		}
+		batch := e.keys[start:end]
+
+		names := make([]string, len(batch))
+		for i, key := range batch {
+			names[i] = key.Name
+		}
+		snapshots, err := snapshotKVKeyValues(e.client, e.accountID, e.namespaceId, names, false)
+		if err != nil {
+			return exported, fmt.Errorf("failed to read values: %v", err)
+		}
+
+		for _, key := range batch {
+			snapshot := snapshots[key.Name]
+			if !snapshot.exists {
+				continue
+			}
+
+			line, err := json.Marshal(newKVExportItem(key, snapshot.value))
+			if err != nil {
+				return exported, fmt.Errorf("failed to marshal %s: %v", key.Name, err)
+			}
+
+			if e.format == KVExportFormatJSON {
+				if exported > 0 {
+					line = append([]byte(","), line...)
+				}
+			} else {
+				line = append(line, '\n')
+			}
+			if _, err := w.Write(line); err != nil {
+				return exported, err
+			}
+			exported++
+		}
+
+		if err := bw.Flush(); err != nil {
+			return exported, err
+		}
+	}
+	return exported, nil
+}
+
+// writeTrailer 写入结束记录
+func (e *KVExport) writeTrailer(w io.Writer, trailer KVExportTrailer) error {
+	data, err := json.Marshal(trailer)
+	if err != nil {
+		return err
+	}
+	if e.format == KVExportFormatJSON {
+		_, err = io.WriteString(w, `],"trailer":`+string(data)+"}\n")
+		return err
+	}
+	_, err = io.WriteString(w, `{"trailer":`+string(data)+"}\n")
+	return err
+}
+
+// newKVExportItem 构造导出条目，非 UTF-8 的值以 base64 编码
+func newKVExportItem(key constants.KVKeys, value string) constants.KVBulkPair {
+	item := constants.KVBulkPair{
+		Key:        key.Name,
+		Value:      value,
+		Expiration: key.Expiration,
+		Metadata:   key.Metadata,
+	}
+	if !utf8.ValidString(value) {
+		item.Value = base64.StdEncoding.EncodeToString([]byte(value))
+		item.Base64 = true
+	}
+	return item
+}
+
+// ImportKVNamespace 从导出文件恢复 KV 命名空间，支持 NDJSON 和 JSON 格式
+func ImportKVNamespace(countryCode, env, namespaceId string, r io.Reader, mode, operator string) (*KVImportResult, error) {
+	if mode == "" {
+		mode = KVImportModeFail
+	}
+	if mode != KVImportModeSkip && mode != KVImportModeOverwrite && mode != KVImportModeFail {
+		return nil, fmt.Errorf("unsupported import mode %q", mode)
+	}
+
+	items, err := ParseKVExport(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &KVImportResult{Total: len(items)}
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	// 已过期的 key 无法写入
+	now := time.Now().Unix()
+	pairs := make([]constants.KVBulkPair, 0, len(items))
+	for _, item := range items {
+		if item.Expiration > 0 && item.Expiration <= now+constants.KVMinExpirationTTL {
+			result.Expired = append(result.Expired, item.Key)
+			continue
+		}
+		pairs = append(pairs, item)
+	}
+
+	if mode != KVImportModeOverwrite {
+		keysResp, err := GetKVKeys(countryCode, env, namespaceId, KVKeysListOptions{All: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list existing keys: %v", err)
+		}
+		existing := make(map[string]bool, len(keysResp.Result))
+		for _, key := range keysResp.Result {
+			existing[key.Name] = true
+		}
+
+		var conflicts []string
+		remaining := pairs[:0]
+		for _, pair := range pairs {
+			if existing[pair.Key] {
+				conflicts = append(conflicts, pair.Key)
+				continue
+			}
+			remaining = append(remaining, pair)
+		}
+
+		if mode == KVImportModeFail && len(conflicts) > 0 {
+			return nil, &KVImportConflictError{NamespaceID: namespaceId, Keys: conflicts}
+		}
+		result.Skipped = conflicts
+		pairs = remaining
+	}
+
+	logger.Info("Importing %d of %d KV keys into namespace %s (mode: %s, skipped: %d, expired: %d)",
+		len(pairs), len(items), namespaceId, mode, len(result.Skipped), len(result.Expired))
+
+	if len(pairs) == 0 {
+		return result, nil
+	}
+
+	result.Write, err = BulkWriteKVKeyValues(countryCode, env, namespaceId, pairs, operator)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParseKVExport 解析导出文件，JSON 格式以 items 字段区分，否则按 NDJSON 逐行解析
+// 文件必须以结束记录收尾且条目数与其一致，否则返回 ErrKVExportIncomplete
+func ParseKVExport(r io.Reader) ([]constants.KVBulkPair, error) {
+	decoder := json.NewDecoder(r)
+
+	var first json.RawMessage
+	if err := decoder.Decode(&first); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: file is empty", ErrKVExportIncomplete)
+		}
+		return nil, fmt.Errorf("invalid export file: %v", err)
+	}
+
+	var probe struct {
+		Items   *[]constants.KVBulkPair `json:"items"`
+		Trailer *KVExportTrailer        `json:"trailer"`
+	}
+	if err := json.Unmarshal(first, &probe); err != nil {
+		return nil, fmt.Errorf("invalid export file: %v", err)
+	}
+	if probe.Items != nil {
+		if err := checkKVExportTrailer(probe.Trailer, len(*probe.Items)); err != nil {
+			return nil, err
+		}
+		return *probe.Items, nil
+	}
+
+	var items []constants.KVBulkPair
+	for line := 1; ; line++ {
+		// 结束记录必须是最后一行
+		probe.Trailer = nil
+		if err := json.Unmarshal(first, &probe); err != nil {
+			return nil, fmt.Errorf("invalid item %d: %v", line, err)
+		}
+		if probe.Trailer != nil {
+			var rest json.RawMessage
+			if err := decoder.Decode(&rest); !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("invalid export file: trailer on line %d is not the last line", line)
+			}
+			if err := checkKVExportTrailer(probe.Trailer, len(items)); err != nil {
+				return nil, err
+			}
+			return items, nil
+		}
+
+		var item constants.KVBulkPair
+		if err := json.Unmarshal(first, &item); err != nil {
+			return nil, fmt.Errorf("invalid item %d: %v", line, err)
+		}
+		if item.Key == "" {
+			return nil, fmt.Errorf("invalid item %d: key is required", line)
+		}
+		items = append(items, item)
+
+		first = nil
+		if err := decoder.Decode(&first); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("%w: missing trailer after %d items", ErrKVExportIncomplete, len(items))
+			}
+			return nil, fmt.Errorf("invalid item %d: %v", line+1, err)
+		}
+	}
+}
+
+// checkKVExportTrailer 校验结束记录存在、没有记录导出失败且条目数一致
+func checkKVExportTrailer(trailer *KVExportTrailer, count int) error {
+	if trailer == nil {
+		return fmt.Errorf("%w: missing trailer", ErrKVExportIncomplete)
+	}
+	if trailer.Error != "" {
+		return fmt.Errorf("%w: export failed after %d items: %s", ErrKVExportIncomplete, trailer.Count, trailer.Error)
+	}
+	if trailer.Count != count {
+		return fmt.Errorf("%w: trailer records %d items, file has %d", ErrKVExportIncomplete, trailer.Count, count)
+	}
+	return nil
+}
+
+// countingWriter 统计写入的字节数
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
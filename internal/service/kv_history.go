@@ -0,0 +1,267 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"openapi/internal/config"
+	"openapi/internal/constants"
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+)
+
+// kvSnapshotWorkers 批量读取旧值时的并发数，Cloudflare API 按账号限流，过高会频繁触发 429
+const kvSnapshotWorkers = 5
+
+// kvValueSnapshot KV key 写入前（或写入后）的值、元数据和过期时间
+type kvValueSnapshot struct {
+	exists     bool
+	value      string
+	metadata   json.RawMessage
+	expiration int64
+}
+
+// KVHistoryDiff 两个历史版本之间的差异
+type KVHistoryDiff struct {
+	NamespaceID string     `json:"namespace_id"`
+	KeyName     string     `json:"key_name"`
+	FromID      uint       `json:"from_id"`
+	ToID        uint       `json:"to_id"`
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	Lines       []DiffLine `json:"lines"`
+	Unified     string     `json:"unified"`
+}
+
+// KVHistoryKeyPatterns 返回批量写入和删除时记录变更历史的 key 通配符，单个 key 的写入总是记录
+// 未配置 kv_history_key_patterns 时返回 nil，表示批量操作记录所有 key 的变更历史
+func KVHistoryKeyPatterns() []string {
+	return config.GlobalConfig.Cloudflare.KVHistoryKeyPatterns
+}
+
+// selectKVHistoryKeys 返回批量操作中需要记录变更历史的 key，默认为全部 key，配置了 kv_history_key_patterns 时只保留匹配的 key
+func selectKVHistoryKeys(keys []string) (map[string]bool, error) {
+	patterns, err := compileKeyPatterns(KVHistoryKeyPatterns())
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if len(patterns) == 0 || matchesAnyPattern(patterns, key) {
+			selected[key] = true
+		}
+	}
+	return selected, nil
+}
+
+// snapshotKVKeyValue 读取 KV key 的当前值和过期时间，key 不存在时不返回错误
+// withMetadata 为 true 时同时读取元数据，需要额外一次请求
+func snapshotKVKeyValue(client *CloudflareClient, accountID, namespaceId, keyName string, withMetadata bool) (kvValueSnapshot, error) {
+	resp, err := readKVKeyValue(client, accountID, namespaceId, keyName)
+	if errors.Is(err, ErrKVKeyNotFound) {
+		return kvValueSnapshot{}, nil
+	}
+	if err != nil {
+		return kvValueSnapshot{}, err
+	}
+	snapshot := kvValueSnapshot{exists: true, value: resp.RawData, expiration: resp.Expiration}
+
+	if withMetadata {
+		metadata, err := readKVKeyMetadata(client, accountID, namespaceId, keyName)
+		if errors.Is(err, ErrKVKeyNotFound) {
+			// 两次读取之间 key 被删除或已过期
+			return kvValueSnapshot{}, nil
+		}
+		if err != nil {
+			return kvValueSnapshot{}, fmt.Errorf("failed to read metadata: %v", err)
+		}
+		snapshot.metadata = metadata
+	}
+	return snapshot, nil
+}
+
+// snapshotKVKeyValues 并发读取多个 KV key 的当前值
+func snapshotKVKeyValues(client *CloudflareClient, accountID, namespaceId string, keys []string, withMetadata bool) (map[string]kvValueSnapshot, error) {
+	snapshots := make(map[string]kvValueSnapshot, len(keys))
+	tasks := make(chan string)
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	for i := 0; i < kvSnapshotWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range tasks {
+				snapshot, err := snapshotKVKeyValue(client, accountID, namespaceId, key, withMetadata)
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = fmt.Errorf("failed to read %s: %v", key, err)
+				}
+				snapshots[key] = snapshot
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, key := range keys {
+		tasks <- key
+	}
+	close(tasks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return snapshots, nil
+}
+
+// newKVValueHistory 根据写入前后的快照构造变更历史，after 为 nil 表示删除
+func newKVValueHistory(countryCode, env, namespaceId, keyName, operation, operator string, before kvValueSnapshot, after *kvValueSnapshot) model.KVValueHistory {
+	record := model.KVValueHistory{
+		Environment:   env,
+		CountryCode:   countryCode,
+		NamespaceID:   namespaceId,
+		KeyName:       keyName,
+		Operation:     operation,
+		OldExists:     before.exists,
+		OldValue:      before.value,
+		OldMetadata:   string(before.metadata),
+		OldExpiration: before.expiration,
+		Operator:      operator,
+	}
+	if after != nil {
+		record.NewExists = true
+		record.NewValue = after.value
+		record.NewMetadata = string(after.metadata)
+		record.NewExpiration = after.expiration
+	}
+	return record
+}
+
+// writtenKVSnapshot 返回写入后的快照，expiration_ttl 换算为过期时间
+func writtenKVSnapshot(value string, opts KVWriteOptions) kvValueSnapshot {
+	expiration := opts.Expiration
+	if opts.ExpirationTTL > 0 {
+		expiration = time.Now().Unix() + opts.ExpirationTTL
+	}
+	return kvValueSnapshot{exists: true, value: value, metadata: opts.Metadata, expiration: expiration}
+}
+
+// recordKVHistory 保存一条变更历史，失败只记录日志，不影响已完成的写入
+func recordKVHistory(record *model.KVValueHistory) {
+	if err := db.DB.Create(record).Error; err != nil {
+		logger.Error("Failed to record KV history for namespace %s, key %s: %v", record.NamespaceID, record.KeyName, err)
+	}
+}
+
+// recordKVHistories 批量保存变更历史
+func recordKVHistories(records []model.KVValueHistory) {
+	if len(records) == 0 {
+		return
+	}
+	if err := db.DB.CreateInBatches(records, 500).Error; err != nil {
+		logger.Error("Failed to record %d KV history entries: %v", len(records), err)
+	}
+}
+
+// ListKVHistory 获取指定国家和环境下 KV key 的变更历史，按时间倒序
+func ListKVHistory(countryCode, env, namespaceId, keyName string, limit int) ([]model.KVValueHistory, error) {
+	var records []model.KVValueHistory
+	query := db.DB.Where("country_code = ? AND environment = ? AND namespace_id = ? AND key_name = ?",
+		countryCode, env, namespaceId, keyName).Order("id DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Find(&records).Error
+	return records, err
+}
+
+// GetKVHistory 获取指定国家和环境下的单条变更历史，属于其他国家或环境的记录视为不存在
+func GetKVHistory(countryCode, env string, id uint) (*model.KVValueHistory, error) {
+	var record model.KVValueHistory
+	err := db.DB.Where("country_code = ? AND environment = ?", countryCode, env).First(&record, id).Error
+	return &record, err
+}
+
+// DiffKVHistory 比较两个历史版本写入后的值
+// compareID 为 0 时比较该版本写入前后的值
+func DiffKVHistory(countryCode, env string, id, compareID uint) (*KVHistoryDiff, error) {
+	record, err := GetKVHistory(countryCode, env, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history %d: %v", id, err)
+	}
+
+	diff := &KVHistoryDiff{
+		NamespaceID: record.NamespaceID,
+		KeyName:     record.KeyName,
+		FromID:      record.ID,
+		ToID:        record.ID,
+		From:        record.OldValue,
+		To:          record.NewValue,
+	}
+
+	if compareID != 0 {
+		compare, err := GetKVHistory(countryCode, env, compareID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get history %d: %v", compareID, err)
+		}
+		if compare.NamespaceID != record.NamespaceID || compare.KeyName != record.KeyName {
+			return nil, fmt.Errorf("history %d and %d belong to different keys", id, compareID)
+		}
+		diff.ToID = compare.ID
+		diff.From = record.NewValue
+		diff.To = compare.NewValue
+	}
+
+	diff.Lines = DiffLines(diff.From, diff.To)
+	diff.Unified = FormatDiff(diff.Lines)
+	return diff, nil
+}
+
+// RestoreKVHistory 将 KV key 恢复到某个历史版本，值、元数据和过期时间一并恢复
+// before 为 true 时恢复到该版本写入前的值，恢复目标为不存在时删除 key
+func RestoreKVHistory(countryCode, env string, id uint, before bool, operator string) (*model.KVValueHistory, error) {
+	record, err := GetKVHistory(countryCode, env, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history %d: %v", id, err)
+	}
+
+	exists, value, metadata, expiration := record.NewExists, record.NewValue, record.NewMetadata, record.NewExpiration
+	if before {
+		exists, value, metadata, expiration = record.OldExists, record.OldValue, record.OldMetadata, record.OldExpiration
+	}
+
+	logger.Info("Restoring KV key %s in namespace %s to history %d (before: %t, exists: %t)",
+		record.KeyName, record.NamespaceID, id, before, exists)
+
+	if !exists {
+		if err := deleteKVKey(record.CountryCode, record.Environment, record.NamespaceID, record.KeyName, operator, model.KVOperationRestore); err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+
+	// 过期时间已过或不足最短 TTL 时，Cloudflare 不接受该过期时间，该版本也早已不可见
+	if expiration > 0 && expiration < time.Now().Unix()+constants.KVMinExpirationTTL {
+		return nil, fmt.Errorf("history %d value expired at %s", id, time.Unix(expiration, 0).UTC().Format(time.RFC3339))
+	}
+
+	_, err = UpdateKVKeyValues(record.CountryCode, record.Environment, record.NamespaceID, record.KeyName, value, KVWriteOptions{
+		Metadata:   json.RawMessage(metadata),
+		Expiration: expiration,
+		Operator:   operator,
+		operation:  model.KVOperationRestore,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// KV 同步动作
+const (
+	KVPromoteCreate        = "create"
+	KVPromoteUpdate        = "update"
+	KVPromoteUnchanged     = "unchanged"
+	KVPromoteMissingSource = "missing_source"
+)
+
+// ErrKVPromotionChanged 预览后源或目标数据发生变化
+var ErrKVPromotionChanged = errors.New("KV promotion plan changed since preview")
+
+// KVLocation 表示某个环境下的 KV 命名空间
+type KVLocation struct {
+	Env         string `json:"env"`
+	CountryCode string `json:"country_code"`
+	NamespaceID string `json:"namespace_id"`
+}
+
+// KVPromoteItem 表示单个 key 的同步计划及执行结果
+type KVPromoteItem struct {
+	Key         string `json:"key"`
+	Action      string `json:"action"`
+	SourceValue string `json:"source_value,omitempty"`
+	TargetValue string `json:"target_value,omitempty"`
+	Diff        string `json:"diff,omitempty"`
+	Applied     bool   `json:"applied"`
+	Error       string `json:"error,omitempty"`
+
+	// SourceExpiration 源 key 的过期时间（Unix 秒），写入目标时一并设置，0 表示不过期
+	SourceExpiration int64 `json:"source_expiration,omitempty"`
+}
+
+// KVPromotePlan 表示一次 KV 同步的预览或执行结果
+type KVPromotePlan struct {
+	Source    KVLocation      `json:"source"`
+	Target    KVLocation      `json:"target"`
+	Items     []KVPromoteItem `json:"items"`
+	Changes   int             `json:"changes"`
+	Hash      string          `json:"hash"` // 确认执行时需回传，用于确认执行的内容与预览一致
+	Confirmed bool            `json:"confirmed"`
+	Applied   int             `json:"applied"`
+	Failed    int             `json:"failed"`
+}
+
+// PreviewKVPromotion 生成从源命名空间同步到目标命名空间的预览
+// keys 与 prefix 至少指定一个，prefix 会从源命名空间中列出所有匹配的 key
+func PreviewKVPromotion(source, target KVLocation, keys []string, prefix string) (*KVPromotePlan, error) {
+	if len(keys) == 0 && prefix == "" {
+		return nil, fmt.Errorf("keys or prefix is required")
+	}
+	if source == target {
+		return nil, fmt.Errorf("source and target are the same")
+	}
+
+	keySet := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		keySet[key] = true
+	}
+
+	if prefix != "" {
+		keysResp, err := GetKVKeys(source.CountryCode, source.Env, source.NamespaceID, KVKeysListOptions{Prefix: prefix, All: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list source keys: %v", err)
+		}
+		for _, key := range keysResp.Result {
+			keySet[key.Name] = true
+		}
+	}
+
+	sortedKeys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	plan := &KVPromotePlan{
+		Source: source,
+		Target: target,
+		Items:  make([]KVPromoteItem, 0, len(sortedKeys)),
+	}
+
+	for _, key := range sortedKeys {
+		item := KVPromoteItem{Key: key}
+
+		sourceValue, sourceExpiration, sourceExists, err := readKVEntryIfExists(source, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read source key %s: %v", key, err)
+		}
+		targetValue, targetExists, err := readKVValueIfExists(target, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read target key %s: %v", key, err)
+		}
+
+		item.SourceValue = sourceValue
+		item.SourceExpiration = sourceExpiration
+		item.TargetValue = targetValue
+
+		switch {
+		case !sourceExists:
+			item.Action = KVPromoteMissingSource
+		case !targetExists:
+			item.Action = KVPromoteCreate
+		case sourceValue == targetValue:
+			item.Action = KVPromoteUnchanged
+		default:
+			item.Action = KVPromoteUpdate
+		}
+
+		if item.Action == KVPromoteCreate || item.Action == KVPromoteUpdate {
+			item.Diff = FormatDiff(DiffLines(targetValue, sourceValue))
+			plan.Changes++
+		}
+
+		plan.Items = append(plan.Items, item)
+	}
+
+	plan.Hash = hashKVPromotePlan(plan)
+	logger.Info("Previewed KV promotion from %s/%s to %s/%s: %d keys, %d changes",
+		source.Env, source.NamespaceID, target.Env, target.NamespaceID, len(plan.Items), plan.Changes)
+
+	return plan, nil
+}
+
+// ApplyKVPromotion 重新生成预览，与确认的预览一致时写入目标命名空间
+func ApplyKVPromotion(source, target KVLocation, keys []string, prefix, previewHash, operator string) (*KVPromotePlan, error) {
+	plan, err := PreviewKVPromotion(source, target, keys, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.Hash != previewHash {
+		return plan, ErrKVPromotionChanged
+	}
+
+	plan.Confirmed = true
+	for i := range plan.Items {
+		item := &plan.Items[i]
+		if item.Action != KVPromoteCreate && item.Action != KVPromoteUpdate {
+			continue
+		}
+
+		// 保留源 key 的元数据和过期时间；源 key 即将过期时 Cloudflare 不接受该过期时间，不再写入
+		var metadata json.RawMessage
+		err := checkKVPromoteExpiration(item.SourceExpiration)
+		if err == nil {
+			metadata, err = GetKVKeyMetadata(source.CountryCode, source.Env, source.NamespaceID, item.Key)
+		}
+		if err == nil {
+			_, err = UpdateKVKeyValues(target.CountryCode, target.Env, target.NamespaceID, item.Key, item.SourceValue, KVWriteOptions{
+				Metadata:   metadata,
+				Expiration: item.SourceExpiration,
+				Operator:   operator,
+			})
+		}
+		if err != nil {
+			item.Error = err.Error()
+			plan.Failed++
+			logger.Error("Failed to promote KV key %s: %v", item.Key, err)
+			continue
+		}
+
+		item.Applied = true
+		plan.Applied++
+	}
+
+	logger.Info("Applied KV promotion from %s/%s to %s/%s: %d applied, %d failed",
+		source.Env, source.NamespaceID, target.Env, target.NamespaceID, plan.Applied, plan.Failed)
+
+	return plan, nil
+}
+
+// readKVValueIfExists 读取 KV key 的值，key 不存在时返回 exists=false
+func readKVValueIfExists(location KVLocation, key string) (value string, exists bool, err error) {
+	value, _, exists, err = readKVEntryIfExists(location, key)
+	return value, exists, err
+}
+
+// readKVEntryIfExists 读取 KV key 的值和过期时间，key 不存在时返回 exists=false
+func readKVEntryIfExists(location KVLocation, key string) (value string, expiration int64, exists bool, err error) {
+	resp, err := GetKVKeyValues(location.CountryCode, location.Env, location.NamespaceID, key)
+	if errors.Is(err, ErrKVKeyNotFound) {
+		return "", 0, false, nil
+	}
+	if err != nil {
+		return "", 0, false, err
+	}
+	return resp.RawData, resp.Expiration, true, nil
+}
+
+// checkKVPromoteExpiration 源 key 的过期时间已过或不足最短 TTL 时返回错误
+func checkKVPromoteExpiration(expiration int64) error {
+	if expiration > 0 && expiration < time.Now().Unix()+constants.KVMinExpirationTTL {
+		return fmt.Errorf("source value expires at %s", time.Unix(expiration, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// hashKVPromotePlan 计算同步计划的摘要
+func hashKVPromotePlan(plan *KVPromotePlan) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%v|%v\n", plan.Source, plan.Target)
+	for _, item := range plan.Items {
+		sourceSum := sha256.Sum256([]byte(item.SourceValue))
+		targetSum := sha256.Sum256([]byte(item.TargetValue))
+		fmt.Fprintf(h, "%s|%s|%x|%x|%d\n", item.Key, item.Action, sourceSum, targetSum, item.SourceExpiration)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
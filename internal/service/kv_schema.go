@@ -0,0 +1,305 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+var (
+	// ErrInvalidKVValueSchema 校验规则本身不合法
+	ErrInvalidKVValueSchema = errors.New("invalid KV value schema")
+
+	// ErrKVValueSchemaNotFound 校验规则不存在
+	ErrKVValueSchemaNotFound = errors.New("KV value schema not found")
+)
+
+// KVViolation 表示一条校验失败信息
+type KVViolation struct {
+	SchemaID         uint   `json:"schema_id"`
+	RuleType         string `json:"rule_type"`
+	InstanceLocation string `json:"instance_location,omitempty"`
+	Message          string `json:"message"`
+}
+
+// KVValidationError KV 值未通过校验
+type KVValidationError struct {
+	NamespaceID string        `json:"namespace_id"`
+	KeyName     string        `json:"key_name"`
+	Violations  []KVViolation `json:"violations"`
+}
+
+func (e *KVValidationError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		if v.InstanceLocation != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", v.InstanceLocation, v.Message))
+		} else {
+			messages = append(messages, v.Message)
+		}
+	}
+	return fmt.Sprintf("value of %s failed validation: %s", e.KeyName, strings.Join(messages, "; "))
+}
+
+// CreateKVValueSchema 创建校验规则
+func CreateKVValueSchema(schema *model.KVValueSchema) error {
+	if err := checkKVValueSchema(schema); err != nil {
+		return err
+	}
+	return db.DB.Create(schema).Error
+}
+
+// ListKVValueSchemas 获取校验规则列表，namespaceId 为空时返回全部
+func ListKVValueSchemas(namespaceId string) ([]model.KVValueSchema, error) {
+	var schemas []model.KVValueSchema
+	query := db.DB
+	if namespaceId != "" {
+		query = query.Where("namespace_id IN ?", []string{namespaceId, model.KVSchemaAnyNamespace})
+	}
+	err := query.Find(&schemas).Error
+	return schemas, err
+}
+
+// UpdateKVValueSchema 更新校验规则，规则不存在时返回 ErrKVValueSchemaNotFound
+func UpdateKVValueSchema(id uint, schema *model.KVValueSchema) error {
+	if err := checkKVValueSchema(schema); err != nil {
+		return err
+	}
+	result := db.DB.Model(&model.KVValueSchema{}).Where("id = ?", id).
+		Select("namespace_id", "key_pattern", "rule_type", "rule", "description", "is_active").
+		Updates(schema)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// MySQL 对内容未变化的行也返回 0，需要再确认规则是否存在
+	var count int64
+	if err := db.DB.Model(&model.KVValueSchema{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %d", ErrKVValueSchemaNotFound, id)
+	}
+	return nil
+}
+
+// DeleteKVValueSchema 删除校验规则，规则不存在时返回 ErrKVValueSchemaNotFound
+func DeleteKVValueSchema(id uint) error {
+	result := db.DB.Delete(&model.KVValueSchema{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: %d", ErrKVValueSchemaNotFound, id)
+	}
+	return nil
+}
+
+// checkKVValueSchema 校验规则本身是否合法，不合法时返回 ErrInvalidKVValueSchema
+func checkKVValueSchema(schema *model.KVValueSchema) error {
+	if _, err := compileKeyPatterns([]string{schema.KeyPattern}); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidKVValueSchema, err)
+	}
+
+	switch schema.RuleType {
+	case model.KVRuleTypeJSONSchema:
+		if _, err := compileJSONSchema(schema.Rule); err != nil {
+			return fmt.Errorf("%w: invalid JSON schema: %v", ErrInvalidKVValueSchema, err)
+		}
+	case model.KVRuleTypeRegex:
+		if _, err := regexp.Compile(schema.Rule); err != nil {
+			return fmt.Errorf("%w: invalid regex: %v", ErrInvalidKVValueSchema, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported rule type %q", ErrInvalidKVValueSchema, schema.RuleType)
+	}
+	return nil
+}
+
+// compileJSONSchema 编译 JSON Schema
+func compileJSONSchema(rule string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", strings.NewReader(rule)); err != nil {
+		return nil, err
+	}
+	return compiler.Compile("schema.json")
+}
+
+// KVValidationErrors 批量写入时多个 key 未通过校验
+type KVValidationErrors []*KVValidationError
+
+func (e KVValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, item := range e {
+		messages = append(messages, item.Error())
+	}
+	return strings.Join(messages, "\n")
+}
+
+// kvValueRule 已编译 key 通配符的校验规则
+type kvValueRule struct {
+	schema  model.KVValueSchema
+	pattern *regexp.Regexp
+}
+
+// ValidateKVValue 使用匹配的校验规则校验 KV 值，未通过时返回 *KVValidationError
+func ValidateKVValue(namespaceId, keyName, value string) error {
+	rules, err := loadKVValueRules(namespaceId)
+	if err != nil {
+		return err
+	}
+	return validateKVValue(rules, namespaceId, keyName, value)
+}
+
+// ValidateKVValues 批量校验 KV 值，未通过时返回 KVValidationErrors
+func ValidateKVValues(namespaceId string, values map[string]string) error {
+	rules, err := loadKVValueRules(namespaceId)
+	if err != nil {
+		return err
+	}
+
+	var validationErrs KVValidationErrors
+	for keyName, value := range values {
+		var ve *KVValidationError
+		if err := validateKVValue(rules, namespaceId, keyName, value); errors.As(err, &ve) {
+			validationErrs = append(validationErrs, ve)
+		}
+	}
+
+	if len(validationErrs) > 0 {
+		sort.Slice(validationErrs, func(i, j int) bool {
+			return validationErrs[i].KeyName < validationErrs[j].KeyName
+		})
+		return validationErrs
+	}
+	return nil
+}
+
+// loadKVValueRules 加载对命名空间生效的校验规则并编译 key 通配符
+// 通配符与 kv_key_patterns 相同，* 匹配任意字符（包括 /）
+func loadKVValueRules(namespaceId string) ([]kvValueRule, error) {
+	var schemas []model.KVValueSchema
+	if err := db.DB.Where("namespace_id IN ? AND is_active = ?", []string{namespaceId, model.KVSchemaAnyNamespace}, true).
+		Find(&schemas).Error; err != nil {
+		return nil, fmt.Errorf("failed to load KV value schemas: %v", err)
+	}
+
+	rules := make([]kvValueRule, 0, len(schemas))
+	for _, schema := range schemas {
+		patterns, err := compileKeyPatterns([]string{schema.KeyPattern})
+		if err != nil {
+			return nil, fmt.Errorf("schema %d: %v", schema.ID, err)
+		}
+		rules = append(rules, kvValueRule{schema: schema, pattern: patterns[0]})
+	}
+	return rules, nil
+}
+
+// validateKVValue 使用给定规则中匹配 key 的部分校验 KV 值
+func validateKVValue(rules []kvValueRule, namespaceId, keyName, value string) error {
+	validationErr := &KVValidationError{
+		NamespaceID: namespaceId,
+		KeyName:     keyName,
+	}
+
+	for _, rule := range rules {
+		if !rule.pattern.MatchString(keyName) {
+			continue
+		}
+		validationErr.Violations = append(validationErr.Violations, checkKVValue(rule.schema, value)...)
+	}
+
+	if len(validationErr.Violations) > 0 {
+		logger.Info("KV value for namespace %s, key %s failed validation with %d violations",
+			namespaceId, keyName, len(validationErr.Violations))
+		return validationErr
+	}
+	return nil
+}
+
+// checkKVValue 使用单条规则校验 KV 值
+func checkKVValue(schema model.KVValueSchema, value string) []KVViolation {
+	violation := func(location, message string) []KVViolation {
+		return []KVViolation{{
+			SchemaID:         schema.ID,
+			RuleType:         schema.RuleType,
+			InstanceLocation: location,
+			Message:          message,
+		}}
+	}
+
+	switch schema.RuleType {
+	case model.KVRuleTypeRegex:
+		re, err := regexp.Compile(schema.Rule)
+		if err != nil {
+			return violation("", fmt.Sprintf("invalid regex rule: %v", err))
+		}
+		if !re.MatchString(value) {
+			return violation("", fmt.Sprintf("value does not match %s", schema.Rule))
+		}
+		return nil
+
+	case model.KVRuleTypeJSONSchema:
+		compiled, err := compileJSONSchema(schema.Rule)
+		if err != nil {
+			return violation("", fmt.Sprintf("invalid JSON schema rule: %v", err))
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader([]byte(value)))
+		decoder.UseNumber()
+		var doc interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			return violation("", fmt.Sprintf("value is not valid JSON: %v", err))
+		}
+		// 值必须是单个 JSON 文档，后面不能再有其他内容
+		if err := decoder.Decode(&struct{}{}); err != io.EOF {
+			return violation("", "value is not valid JSON: unexpected data after top-level value")
+		}
+
+		var ve *jsonschema.ValidationError
+		if err := compiled.Validate(doc); errors.As(err, &ve) {
+			var violations []KVViolation
+			collectSchemaViolations(schema, ve, &violations)
+			return violations
+		} else if err != nil {
+			return violation("", err.Error())
+		}
+		return nil
+	}
+
+	return violation("", fmt.Sprintf("unsupported rule type %q", schema.RuleType))
+}
+
+// collectSchemaViolations 收集 JSON Schema 校验错误的叶子节点
+func collectSchemaViolations(schema model.KVValueSchema, ve *jsonschema.ValidationError, violations *[]KVViolation) {
+	if len(ve.Causes) == 0 {
+		location := ve.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		*violations = append(*violations, KVViolation{
+			SchemaID:         schema.ID,
+			RuleType:         schema.RuleType,
+			InstanceLocation: location,
+			Message:          ve.Message,
+		})
+		return
+	}
+	for _, cause := range ve.Causes {
+		collectSchemaViolations(schema, cause, violations)
+	}
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+
+	"openapi/internal/constants"
+	"openapi/internal/logger"
+)
+
+// Pages 配置变更动作
+const (
+	PagesConfigAdd    = "add"
+	PagesConfigUpdate = "update"
+	PagesConfigRemove = "remove"
+)
+
+// Pages 环境变量类型
+const (
+	PagesEnvVarPlainText  = "plain_text"
+	PagesEnvVarSecretText = "secret_text"
+)
+
+// pagesSecretMask 变更预览中替代 secret 值的掩码
+const pagesSecretMask = "******"
+
+// ErrPagesConfigChanged 预览后项目配置发生变化
+var ErrPagesConfigChanged = errors.New("Pages project configuration changed since preview")
+
+// PagesConfigPatch Pages 项目配置的局部更新，未指定的字段保持不变
+type PagesConfigPatch struct {
+	BuildCommand   *string
+	DestinationDir *string
+	RootDir        *string
+	Preview        *PagesDeploymentConfigPatch
+	Production     *PagesDeploymentConfigPatch
+}
+
+// PagesDeploymentConfigPatch 单个环境部署配置的局部更新
+// map 中值为 nil 表示删除该绑定或环境变量
+type PagesDeploymentConfigPatch struct {
+	CompatibilityDate  *string
+	CompatibilityFlags *[]string
+	EnvVars            map[string]*constants.EnvVarBinding
+	KVNamespaces       map[string]*string // 绑定名 -> 命名空间 ID
+	R2Buckets          map[string]*string // 绑定名 -> 桶名称
+	D1Databases        map[string]*string // 绑定名 -> 数据库 ID
+}
+
+// PagesConfigChange 单项配置变更
+type PagesConfigChange struct {
+	Environment string      `json:"environment"` // preview、production 或 build
+	Field       string      `json:"field"`
+	Name        string      `json:"name,omitempty"`
+	Action      string      `json:"action"`
+	Old         interface{} `json:"old,omitempty"`
+	New         interface{} `json:"new,omitempty"`
+}
+
+// PagesConfigPlan Pages 项目配置变更预览或执行结果
+type PagesConfigPlan struct {
+	ProjectName string                  `json:"project_name"`
+	Changes     []PagesConfigChange     `json:"changes"`
+	Hash        string                  `json:"hash"` // 确认执行时需回传
+	Confirmed   bool                    `json:"confirmed"`
+	Project     *constants.PagesProject `json:"project,omitempty"` // 执行后的项目配置
+
+	payload map[string]interface{}
+}
+
+// PreviewPagesConfigUpdate 对比当前项目配置，返回将要执行的变更
+func PreviewPagesConfigUpdate(countryCode, env, projectName string, patch PagesConfigPatch) (*PagesConfigPlan, error) {
+	project, err := GetPagesProjectByName(countryCode, env, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pages project: %v", err)
+	}
+
+	plan := &PagesConfigPlan{
+		ProjectName: projectName,
+		Changes:     make([]PagesConfigChange, 0),
+		payload:     make(map[string]interface{}),
+	}
+
+	buildPayload := make(map[string]interface{})
+	current := project.Build_config
+	diffPagesScalar(plan, buildPayload, "build", "build_command", current.BuildCommand, patch.BuildCommand)
+	diffPagesScalar(plan, buildPayload, "build", "destination_dir", current.DestinationDir, patch.DestinationDir)
+	diffPagesScalar(plan, buildPayload, "build", "root_dir", current.RootDir, patch.RootDir)
+	if len(buildPayload) > 0 {
+		plan.payload["build_config"] = buildPayload
+	}
+
+	deploymentPayload := make(map[string]interface{})
+	for _, item := range []struct {
+		environment string
+		current     constants.DeploymentConfig
+		patch       *PagesDeploymentConfigPatch
+	}{
+		{PagesEnvPreview, project.Deployment_configs.Preview, patch.Preview},
+		{PagesEnvProduction, project.Deployment_configs.Production, patch.Production},
+	} {
+		if item.patch == nil {
+			continue
+		}
+		if envPayload := diffPagesDeploymentConfig(plan, item.environment, item.current, item.patch); len(envPayload) > 0 {
+			deploymentPayload[item.environment] = envPayload
+		}
+	}
+	if len(deploymentPayload) > 0 {
+		plan.payload["deployment_configs"] = deploymentPayload
+	}
+
+	plan.Hash = hashPagesConfigPlan(project, plan.Changes, plan.payload)
+	logger.Info("Previewed configuration update for Pages project %s: %d changes", projectName, len(plan.Changes))
+
+	return plan, nil
+}
+
+// ApplyPagesConfigUpdate 重新生成预览，与确认的预览一致时更新项目配置
+func ApplyPagesConfigUpdate(countryCode, env, projectName string, patch PagesConfigPatch, previewHash, operator string) (*PagesConfigPlan, error) {
+	plan, err := PreviewPagesConfigUpdate(countryCode, env, projectName, patch)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Hash != previewHash {
+		return plan, ErrPagesConfigChanged
+	}
+
+	plan.Confirmed = true
+	if len(plan.Changes) == 0 {
+		return plan, nil
+	}
+
+	client, accountID, err := loadCloudflareClient(countryCode, env)
+	if err != nil {
+		return nil, err
+	}
+
+	reqURL := fmt.Sprintf("%s/%s/pages/projects/%s", constants.CFBaseURL, accountID, url.PathEscape(projectName))
+
+	logger.Info("Updating configuration of Pages project %s by %s: %d changes", projectName, operator, len(plan.Changes))
+
+	var cfResp constants.CFSingleResponse[constants.PagesProject]
+	if err := client.DoJSON("PATCH", reqURL, plan.payload, &cfResp); err != nil {
+		return nil, err
+	}
+
+	plan.Project = &cfResp.Result
+	return plan, nil
+}
+
+// diffPagesDeploymentConfig 对比单个环境的部署配置，返回该环境的 PATCH 请求体
+func diffPagesDeploymentConfig(plan *PagesConfigPlan, environment string, current constants.DeploymentConfig, patch *PagesDeploymentConfigPatch) map[string]interface{} {
+	payload := make(map[string]interface{})
+
+	diffPagesScalar(plan, payload, environment, "compatibility_date", current.CompatibilityDate, patch.CompatibilityDate)
+
+	if patch.CompatibilityFlags != nil && !reflect.DeepEqual(normalizeFlags(current.CompatibilityFlags), normalizeFlags(*patch.CompatibilityFlags)) {
+		plan.Changes = append(plan.Changes, PagesConfigChange{
+			Environment: environment,
+			Field:       "compatibility_flags",
+			Action:      PagesConfigUpdate,
+			Old:         current.CompatibilityFlags,
+			New:         *patch.CompatibilityFlags,
+		})
+		payload["compatibility_flags"] = *patch.CompatibilityFlags
+	}
+
+	// 环境变量，secret 的当前值不可读，指定时总是更新
+	envVars := make(map[string]interface{})
+	for _, name := range sortedPatchKeys(patch.EnvVars) {
+		next := patch.EnvVars[name]
+		old, exists := current.Env_vars[name]
+		change := PagesConfigChange{Environment: environment, Field: "env_vars", Name: name}
+		if exists {
+			change.Old = maskEnvVar(old)
+		}
+
+		switch {
+		case next == nil && !exists:
+			continue
+		case next == nil:
+			change.Action = PagesConfigRemove
+			envVars[name] = nil
+		default:
+			value := *next
+			if value.Type == "" {
+				value.Type = PagesEnvVarPlainText
+			}
+			if exists && value.Type == PagesEnvVarPlainText && old.Type == value.Type && old.Value == value.Value {
+				continue
+			}
+			change.Action = PagesConfigAdd
+			if exists {
+				change.Action = PagesConfigUpdate
+			}
+			change.New = maskEnvVar(value)
+			envVars[name] = value
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	if len(envVars) > 0 {
+		payload["env_vars"] = envVars
+	}
+
+	kvCurrent := make(map[string]string, len(current.KV_namespaces))
+	for name, binding := range current.KV_namespaces {
+		kvCurrent[name] = binding.Namespace_ID
+	}
+	diffPagesBindings(plan, payload, environment, "kv_namespaces", kvCurrent, patch.KVNamespaces, func(id string) interface{} {
+		return constants.KVInfo{Namespace_ID: id}
+	})
+
+	r2Current := make(map[string]string, len(current.R2_buckets))
+	for name, binding := range current.R2_buckets {
+		r2Current[name] = binding.Name
+	}
+	diffPagesBindings(plan, payload, environment, "r2_buckets", r2Current, patch.R2Buckets, func(name string) interface{} {
+		return constants.R2BucketBinding{Name: name}
+	})
+
+	d1Current := make(map[string]string, len(current.D1_databases))
+	for name, binding := range current.D1_databases {
+		d1Current[name] = binding.ID
+	}
+	diffPagesBindings(plan, payload, environment, "d1_databases", d1Current, patch.D1Databases, func(id string) interface{} {
+		return constants.D1DatabaseBinding{ID: id}
+	})
+
+	return payload
+}
+
+// diffPagesBindings 对比以绑定名为 key 的资源绑定，newBinding 生成写入 Cloudflare 的绑定结构
+func diffPagesBindings(plan *PagesConfigPlan, payload map[string]interface{}, environment, field string, current map[string]string, patch map[string]*string, newBinding func(string) interface{}) {
+	bindings := make(map[string]interface{})
+	for _, name := range sortedPatchKeys(patch) {
+		next := patch[name]
+		old, exists := current[name]
+		change := PagesConfigChange{Environment: environment, Field: field, Name: name}
+		if exists {
+			change.Old = old
+		}
+
+		switch {
+		case next == nil && !exists:
+			continue
+		case next == nil:
+			change.Action = PagesConfigRemove
+			bindings[name] = nil
+		case exists && old == *next:
+			continue
+		default:
+			change.Action = PagesConfigAdd
+			if exists {
+				change.Action = PagesConfigUpdate
+			}
+			change.New = *next
+			bindings[name] = newBinding(*next)
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	if len(bindings) > 0 {
+		payload[field] = bindings
+	}
+}
+
+// diffPagesScalar 对比单个字符串配置项
+func diffPagesScalar(plan *PagesConfigPlan, payload map[string]interface{}, environment, field, current string, next *string) {
+	if next == nil || *next == current {
+		return
+	}
+	plan.Changes = append(plan.Changes, PagesConfigChange{
+		Environment: environment,
+		Field:       field,
+		Action:      PagesConfigUpdate,
+		Old:         current,
+		New:         *next,
+	})
+	payload[field] = *next
+}
+
+// maskEnvVar 隐藏 secret 类型环境变量的值
+func maskEnvVar(envVar constants.EnvVarBinding) constants.EnvVarBinding {
+	if envVar.Type == PagesEnvVarSecretText {
+		envVar.Value = pagesSecretMask
+	}
+	return envVar
+}
+
+// normalizeFlags 返回排序后的 compatibility flags，nil 与空列表视为相同
+func normalizeFlags(flags []string) []string {
+	normalized := append([]string{}, flags...)
+	sort.Strings(normalized)
+	return normalized
+}
+
+// sortedPatchKeys 返回排序后的 map key，保证变更顺序稳定
+func sortedPatchKeys[T any](m map[string]*T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hashPagesConfigPlan 根据当前配置、变更和请求体计算摘要
+// 变更中的 secret 值已被掩码，请求体包含原值，只修改 secret 值时摘要也会变化
+func hashPagesConfigPlan(project *constants.PagesProject, changes []PagesConfigChange, payload map[string]interface{}) string {
+	h := sha256.New()
+	state, _ := json.Marshal(struct {
+		Build       constants.PagesBuildConfig
+		Deployments constants.DeploymentConfigs
+	}{project.Build_config, project.Deployment_configs})
+	h.Write(state)
+	data, _ := json.Marshal(changes)
+	h.Write(data)
+	body, _ := json.Marshal(payload)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"openapi/internal/db"
+	"openapi/internal/logger"
+	"openapi/internal/model"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// releaseVersionPattern 版本目录名格式，如 v1.2.3、v1.2.3-rc.1
+var releaseVersionPattern = regexp.MustCompile(`^v\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+
+// mysqlDuplicateEntry MySQL 唯一索引冲突的错误码
+const mysqlDuplicateEntry = 1062
+
+// releaseStaleAfter 没有后台任务的发布超过该时间仍未结束时视为已中断，不再阻止新的发布
+const releaseStaleAfter = time.Hour
+
+// ErrReleaseInProgress 同一 KV key 已有未结束的发布
+var ErrReleaseInProgress = errors.New("another release is in progress")
+
+// ReleaseOptions 发布参数
+type ReleaseOptions struct {
+	BucketName  string
+	SourceDir   string // 为空时不复制，要求版本目录已存在
+	Version     string
+	NamespaceID string
+	KeyName     string
+	Operator    string
+}
+
+// Release 发布静态站点：版本目录非空后将 KV 版本 key 指向新版本
+// 指定构建目录时创建后台任务复制并校验，任务完成后再切换 KV，立即返回状态为 copying、带 JobID 的记录；否则同步切换并返回最终状态
+// 任一步骤失败时 KV 保持不变，记录状态为 failed；同一 key 已有未结束的发布时返回 ErrReleaseInProgress
+func Release(countryCode, env string, opts ReleaseOptions) (*model.ReleaseRecord, error) {
+	if !releaseVersionPattern.MatchString(opts.Version) {
+		return nil, fmt.Errorf("invalid version %q, expected vX.Y.Z", opts.Version)
+	}
+
+	// 复制前先校验版本值，避免复制完成后才被 KV 校验规则拒绝
+	if err := ValidateKVValue(opts.NamespaceID, opts.KeyName, opts.Version); err != nil {
+		return nil, err
+	}
+
+	activeKey := releaseActiveKey(env, opts.NamespaceID, opts.KeyName)
+	if err := expireInterruptedReleases(activeKey); err != nil {
+		return nil, err
+	}
+
+	previous, previousExists, err := readKVValueIfExists(KVLocation{
+		Env:         env,
+		CountryCode: countryCode,
+		NamespaceID: opts.NamespaceID,
+	}, opts.KeyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current version: %v", err)
+	}
+
+	record := &model.ReleaseRecord{
+		Environment:     env,
+		CountryCode:     countryCode,
+		BucketName:      opts.BucketName,
+		SourceDir:       opts.SourceDir,
+		Version:         opts.Version,
+		NamespaceID:     opts.NamespaceID,
+		KeyName:         opts.KeyName,
+		PreviousVersion: previous,
+		PreviousExists:  previousExists,
+		Status:          model.ReleaseStatusCopying,
+		Operator:        opts.Operator,
+		ActiveKey:       &activeKey,
+	}
+	if opts.SourceDir == "" {
+		record.Status = model.ReleaseStatusVerifying
+	}
+	// active_key 唯一索引保证并发发布同一 key 时只有一个能创建记录
+	err = db.DB.Create(record).Error
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return nil, fmt.Errorf("%w: %s in namespace %s", ErrReleaseInProgress, opts.KeyName, opts.NamespaceID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create release record: %v", err)
+	}
+
+	logger.Info("Release %d: %s/%s -> %s, %s in namespace %s (previous: %s)",
+		record.ID, opts.BucketName, opts.SourceDir, opts.Version, opts.KeyName, opts.NamespaceID, previous)
+
+	if opts.SourceDir == "" {
+		return completeRelease(record)
+	}
+
+	job, err := createJob(&model.Job{
+		Type:        model.JobTypeRelease,
+		Environment: env,
+		CountryCode: countryCode,
+		BucketName:  opts.BucketName,
+		SourceDir:   strings.TrimSuffix(opts.SourceDir, "/") + "/",
+		TargetDir:   opts.Version + "/",
+		Verify:      true, // 版本目录必须与构建目录完全一致，部分复制时不切换 KV
+		ReleaseID:   record.ID,
+		Operator:    opts.Operator,
+	})
+	if err != nil {
+		return failRelease(record, err)
+	}
+	record.JobID = job.ID
+	if err := db.DB.Model(record).UpdateColumn("job_id", job.ID).Error; err != nil {
+		logger.Error("Failed to record job %d of release %d: %v", job.ID, record.ID, err)
+	}
+	return record, nil
+}
+
+// runReleaseJob 执行发布任务：复制并校验构建目录，成功后切换 KV 版本
+func runReleaseJob(ctx context.Context, job *model.Job) error {
+	record, err := GetRelease(job.ReleaseID)
+	if err != nil {
+		return err
+	}
+	switch record.Status {
+	case model.ReleaseStatusCopying:
+	case model.ReleaseStatusVerifying, model.ReleaseStatusSwitching:
+		// 复制已完成，服务重启前未切换完 KV
+		_, err = completeRelease(record)
+		return err
+	default:
+		return fmt.Errorf("release %d is already %s", record.ID, record.Status)
+	}
+
+	err = runR2Job(ctx, job)
+	switch {
+	case ctx.Err() != nil:
+		err = fmt.Errorf("release job cancelled")
+	case err == nil && job.FailedObjects > 0:
+		err = fmt.Errorf("%d objects failed", job.FailedObjects)
+	}
+	if err != nil {
+		failRelease(record, fmt.Errorf("failed to copy %s to %s: %v", record.SourceDir, record.Version, err))
+		return err
+	}
+
+	_, err = completeRelease(record)
+	return err
+}
+
+// completeRelease 检查版本目录非空后切换 KV 版本
+func completeRelease(record *model.ReleaseRecord) (*model.ReleaseRecord, error) {
+	setReleaseStatus(record, model.ReleaseStatusVerifying)
+	ok, err := r2DirectoryHasObjects(record.CountryCode, record.Environment, record.BucketName, record.Version)
+	if err != nil {
+		return failRelease(record, fmt.Errorf("failed to check version directory: %v", err))
+	}
+	if !ok {
+		return failRelease(record, fmt.Errorf("version directory %s/ does not exist or is empty", record.Version))
+	}
+
+	setReleaseStatus(record, model.ReleaseStatusSwitching)
+	if _, err := UpdateKVKeyValues(record.CountryCode, record.Environment, record.NamespaceID, record.KeyName, record.Version, KVWriteOptions{
+		Operator: record.Operator,
+	}); err != nil {
+		return failRelease(record, fmt.Errorf("failed to switch %s: %v", record.KeyName, err))
+	}
+
+	setReleaseStatus(record, model.ReleaseStatusSucceeded)
+	logger.Info("Release %d succeeded: %s = %s", record.ID, record.KeyName, record.Version)
+	return record, nil
+}
+
+// failRelease 将发布标记为失败
+func failRelease(record *model.ReleaseRecord, err error) (*model.ReleaseRecord, error) {
+	record.Error = err.Error()
+	setReleaseStatus(record, model.ReleaseStatusFailed)
+	logger.Error("Release %d failed: %v", record.ID, err)
+	return record, err
+}
+
+// releaseActiveKey 同一环境、命名空间和 key 的发布使用相同的摘要
+func releaseActiveKey(env, namespaceId, keyName string) string {
+	sum := sha256.Sum256([]byte(env + "\x00" + namespaceId + "\x00" + keyName))
+	return hex.EncodeToString(sum[:])
+}
+
+// expireInterruptedReleases 将已中断的发布标记为失败并释放 key：
+// 没有后台任务且超过 releaseStaleAfter 未更新，或后台任务已结束但发布仍未结束
+func expireInterruptedReleases(activeKey string) error {
+	finishedJobs := db.DB.Model(&model.Job{}).Select("id").
+		Where("status IN ?", []string{model.JobStatusSucceeded, model.JobStatusFailed, model.JobStatusCancelled})
+	if err := db.DB.Model(&model.ReleaseRecord{}).
+		Where("active_key = ?", activeKey).
+		Where("(job_id = 0 AND updated_at < ?) OR job_id IN (?)", time.Now().Add(-releaseStaleAfter), finishedJobs).
+		Updates(map[string]interface{}{
+			"status":     model.ReleaseStatusFailed,
+			"error":      "release interrupted",
+			"active_key": nil,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to check running releases: %v", err)
+	}
+	return nil
+}
+
+// RollbackRelease 将 KV 版本 key 恢复为发布前的值
+// 仅当 key 当前仍指向该次发布的版本时允许回滚，避免覆盖之后的发布
+func RollbackRelease(countryCode, env string, id uint, operator string) (*model.ReleaseRecord, error) {
+	record, err := GetRelease(id)
+	if err != nil {
+		return nil, err
+	}
+	if record.Environment != env {
+		return nil, fmt.Errorf("release %d belongs to env %s, not %s", id, record.Environment, env)
+	}
+	if record.Status != model.ReleaseStatusSucceeded {
+		return nil, fmt.Errorf("release %d is %s, only succeeded releases can be rolled back", id, record.Status)
+	}
+
+	current, exists, err := readKVValueIfExists(KVLocation{
+		Env:         record.Environment,
+		CountryCode: record.CountryCode,
+		NamespaceID: record.NamespaceID,
+	}, record.KeyName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read current version: %v", err)
+	}
+	if !exists || current != record.Version {
+		return nil, fmt.Errorf("%s is now %q, not %q released by %d", record.KeyName, current, record.Version, id)
+	}
+
+	logger.Info("Rolling back release %d: %s %s -> %s by %s",
+		id, record.KeyName, record.Version, record.PreviousVersion, operator)
+
+	if record.PreviousExists {
+		_, err = UpdateKVKeyValues(record.CountryCode, record.Environment, record.NamespaceID, record.KeyName, record.PreviousVersion, KVWriteOptions{
+			Operator:  operator,
+			operation: model.KVOperationRestore,
+		})
+	} else {
+		err = deleteKVKey(record.CountryCode, record.Environment, record.NamespaceID, record.KeyName, operator, model.KVOperationRestore)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %v", record.KeyName, err)
+	}
+
+	setReleaseStatus(record, model.ReleaseStatusRolledBack)
+	return record, nil
+}
+
+// GetRelease 获取发布记录
+func GetRelease(id uint) (*model.ReleaseRecord, error) {
+	var record model.ReleaseRecord
+	if err := db.DB.First(&record, id).Error; err != nil {
+		return nil, fmt.Errorf("failed to get release %d: %v", id, err)
+	}
+	return &record, nil
+}
+
+// ListReleases 获取发布记录，按时间倒序，namespaceId、keyName 为空时不过滤
+func ListReleases(env, namespaceId, keyName string, limit int) ([]model.ReleaseRecord, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	query := db.DB.Where("environment = ?", env)
+	if namespaceId != "" {
+		query = query.Where("namespace_id = ?", namespaceId)
+	}
+	if keyName != "" {
+		query = query.Where("key_name = ?", keyName)
+	}
+
+	var records []model.ReleaseRecord
+	err := query.Order("id DESC").Limit(limit).Find(&records).Error
+	return records, err
+}
+
+// setReleaseStatus 更新发布状态，发布结束时释放 key，失败时只记录日志
+func setReleaseStatus(record *model.ReleaseRecord, status string) {
+	record.Status = status
+	if status == model.ReleaseStatusSucceeded || status == model.ReleaseStatusFailed || status == model.ReleaseStatusRolledBack {
+		record.ActiveKey = nil
+	}
+	if err := db.DB.Model(record).Select("status", "error", "active_key").Updates(record).Error; err != nil {
+		logger.Error("Failed to update release %d status to %s: %v", record.ID, status, err)
+	}
+}
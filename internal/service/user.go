@@ -63,6 +63,15 @@ func ValidateUser(username, password string) (*model.User, error) {
 	return &user, nil
 }
 
+// IsAdmin 判断用户是否为未禁用的管理员
+func IsAdmin(userID uint) (bool, error) {
+	var user model.User
+	if err := db.DB.First(&user, userID).Error; err != nil {
+		return false, ErrUserNotFound
+	}
+	return user.IsAdmin && user.Status == 1, nil
+}
+
 // GetRemainingAttempts 获取剩余尝试次数和锁定剩余时间
 func GetRemainingAttempts(username string) (attempts int, lockoutRemaining time.Duration, err error) {
 	var user model.User
@@ -52,6 +52,17 @@ func main() {
 		logger.Info("Successfully loaded all configs")
 	}
 
+	// 继续执行重启前未完成的后台任务
+	if err := service.ResumeJobs(); err != nil {
+		logger.Error("Failed to resume jobs: %v", err)
+	}
+
+	// 定时执行 R2 版本目录保留策略
+	service.StartR2RetentionScheduler()
+
+	// 定时清理 R2 回收站中过期的目录
+	service.StartR2TrashPurger()
+
 	// 设置路由
 	r := router.SetupRouter()
 